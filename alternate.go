@@ -0,0 +1,268 @@
+package easyrss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//A Podcasting 2.0 <podcast:alternateEnclosure>, one rendition of an episode's media
+type AlternateEnclosure struct {
+	Type      string  //MIME type
+	Length    uint64  //Size in bytes
+	Bitrate   float64 //Average bits per second
+	Height    int     //Video height in pixels
+	Lang      string
+	Title     string //Short label, e.g. "Standard" or "High quality"
+	Rel       string //Renditions sharing a rel are alternatives to each other
+	Codecs    string //RFC 6381 codecs string, e.g. "opus" or "avc1.64001F, mp4a.40.2"
+	Default   bool   //Whether this rendition is the one in the item's <enclosure>
+	Sources   []EnclosureSource
+	Integrity *Integrity
+}
+
+//A <podcast:source>, one place to download a rendition from
+type EnclosureSource struct {
+	URI         string //http(s), ipfs:, magnet: or any other URI
+	ContentType string //Only set when it differs from the rendition's type, e.g. "application/x-bittorrent"
+}
+
+//A <podcast:integrity> check for a rendition
+type Integrity struct {
+	Type  string //"sri" or "pgp-signature"
+	Value string
+}
+
+//What to look for when picking a rendition with SelectEnclosure. The zero value picks the best quality default rendition.
+type EnclosurePreference struct {
+	Codecs     []string //Codecs to favour, most preferred first, matched case-insensitively against the start of each codec in Codecs
+	Types      []string //MIME types to favour, most preferred first
+	MaxBitrate float64  //Skip renditions above this many bits per second, 0 for no limit
+	MaxHeight  int      //Skip renditions taller than this, 0 for no limit
+	Lang       string   //Language to favour
+}
+
+func parseAlternateEnclosure(n node) AlternateEnclosure {
+	alt := AlternateEnclosure{
+		Type:    n.Attr("type"),
+		Lang:    n.Attr("lang"),
+		Title:   n.Attr("title"),
+		Rel:     n.Attr("rel"),
+		Codecs:  n.Attr("codecs"),
+		Default: strings.EqualFold(strings.TrimSpace(n.Attr("default")), "true"),
+	}
+	alt.Length, _ = strconv.ParseUint(strings.TrimSpace(n.Attr("length")), 10, 64)
+	alt.Bitrate, _ = strconv.ParseFloat(strings.TrimSpace(n.Attr("bitrate")), 64)
+	alt.Height, _ = strconv.Atoi(strings.TrimSpace(n.Attr("height")))
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		switch activeElem.Name() {
+		case "source":
+			alt.Sources = append(alt.Sources, EnclosureSource{URI: strings.TrimSpace(activeElem.Attr("uri")), ContentType: activeElem.Attr("contentType")})
+		case "integrity":
+			alt.Integrity = &Integrity{Type: strings.ToLower(activeElem.Attr("type")), Value: strings.TrimSpace(activeElem.Attr("value"))}
+		}
+	}
+	return alt
+}
+
+//Returns the first http or https source, which any client can download. Returns an empty string if there is none.
+func (a *AlternateEnclosure) HTTPSource() string {
+	for _, source := range a.Sources {
+		if uri := strings.ToLower(source.URI); strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://") {
+			return source.URI
+		}
+	}
+	return ""
+}
+
+//Checks downloaded media against the rendition's SRI hash. Returns an error if there is no integrity check, if it isn't an SRI
+//hash, or if data doesn't match.
+func (a *AlternateEnclosure) Verify(data []byte) error {
+	return a.VerifyReader(bytes.NewReader(data))
+}
+
+//Same as Verify, but reads the media from r so large files don't need to be held in memory.
+func (a *AlternateEnclosure) VerifyReader(r io.Reader) error {
+	if a.Integrity == nil {
+		return notPopulated(ElementItem, NamespacePodcast, "integrity")
+	}
+	if a.Integrity.Type != "sri" {
+		return fmt.Errorf("%w: %q", ErrUnsupportedIntegrity, a.Integrity.Type)
+	}
+	return verifySRI(r, a.Integrity.Value)
+}
+
+//Checks data against a Subresource Integrity metadata string such as "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC".
+//As in browsers, only the hashes using the strongest of sha256, sha384 and sha512 are considered, and any one of them matching is
+//enough. Returns ErrIntegrityMismatch if none matches and ErrUnsupportedIntegrity if no hash uses a known algorithm.
+func VerifySRI(data []byte, integrity string) error {
+	return verifySRI(bytes.NewReader(data), integrity)
+}
+
+func verifySRI(r io.Reader, integrity string) error {
+	strongest := 0
+	var expected [][]byte
+	for _, token := range strings.Fields(integrity) {
+		if options := strings.IndexByte(token, '?'); options >= 0 {
+			token = token[:options]
+		}
+		dash := strings.IndexByte(token, '-')
+		if dash < 0 {
+			continue
+		}
+		strength := sriStrength(token[:dash])
+		digest, err := base64.StdEncoding.DecodeString(token[dash+1:])
+		if strength == 0 || err != nil || strength < strongest {
+			continue
+		}
+		if strength > strongest {
+			strongest, expected = strength, nil
+		}
+		expected = append(expected, digest)
+	}
+	if strongest == 0 {
+		return ErrUnsupportedIntegrity
+	}
+	var h hash.Hash
+	switch strongest {
+	case 256:
+		h = sha256.New()
+	case 384:
+		h = sha512.New384()
+	default:
+		h = sha512.New()
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	actual := h.Sum(nil)
+	for _, digest := range expected {
+		if bytes.Equal(digest, actual) {
+			return nil
+		}
+	}
+	return ErrIntegrityMismatch
+}
+
+func sriStrength(algorithm string) int {
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		return 256
+	case "sha384":
+		return 384
+	case "sha512":
+		return 512
+	}
+	return 0
+}
+
+//Returns the item's Podcasting 2.0 renditions. If the item doesn't contain Podcasting 2.0 Extensions or has no "alternateEnclosure" tags, will return nil and an error.
+func (i *Item) AlternateEnclosures() ([]AlternateEnclosure, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(i.podcast.alternateEnclosures) == 0 {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "alternateEnclosure")
+	}
+	return i.podcast.alternateEnclosures, nil
+}
+
+//Picks the rendition that best matches p among the item's alternate enclosures and its regular enclosure. Renditions over the
+//bitrate or height limits are only considered when nothing else is left, in which case the smallest one wins. Otherwise a
+//preferred codec beats a preferred type, which beats the language, which beats quality. Ties go to the default rendition. If
+//the item has no media at all, will return nil and an error.
+func (i *Item) SelectEnclosure(p EnclosurePreference) (*AlternateEnclosure, error) {
+	candidates := append([]AlternateEnclosure(nil), i.podcast.alternateEnclosures...)
+	if i.hasEnclosure && i.enclosure.url != "" {
+		known := false
+		for _, alt := range candidates {
+			for _, source := range alt.Sources {
+				known = known || source.URI == i.enclosure.url
+			}
+		}
+		if !known {
+			candidates = append(candidates, AlternateEnclosure{
+				Type:    i.enclosure.mediaType,
+				Length:  i.enclosure.size,
+				Default: true,
+				Sources: []EnclosureSource{{URI: i.enclosure.url}},
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, notPopulated(ElementItem, "", "enclosure")
+	}
+
+	var withinLimits []int
+	for c := range candidates {
+		if (p.MaxBitrate == 0 || candidates[c].Bitrate <= p.MaxBitrate) && (p.MaxHeight == 0 || candidates[c].Height <= p.MaxHeight) {
+			withinLimits = append(withinLimits, c)
+		}
+	}
+	if len(withinLimits) == 0 {
+		smallest := 0
+		for c := range candidates {
+			if candidates[c].Bitrate < candidates[smallest].Bitrate || candidates[c].Bitrate == candidates[smallest].Bitrate && candidates[c].Height < candidates[smallest].Height {
+				smallest = c
+			}
+		}
+		return &candidates[smallest], nil
+	}
+
+	best := withinLimits[0]
+	for _, c := range withinLimits[1:] {
+		if enclosureBetter(&candidates[c], &candidates[best], &p) {
+			best = c
+		}
+	}
+	return &candidates[best], nil
+}
+
+//Whether a is a better match for p than b
+func enclosureBetter(a, b *AlternateEnclosure, p *EnclosurePreference) bool {
+	if ra, rb := codecRank(a.Codecs, p.Codecs), codecRank(b.Codecs, p.Codecs); ra != rb {
+		return ra < rb
+	}
+	if ra, rb := typeRank(a.Type, p.Types), typeRank(b.Type, p.Types); ra != rb {
+		return ra < rb
+	}
+	if p.Lang != "" {
+		if la, lb := strings.EqualFold(a.Lang, p.Lang), strings.EqualFold(b.Lang, p.Lang); la != lb {
+			return la
+		}
+	}
+	if a.Height != b.Height {
+		return a.Height > b.Height
+	}
+	if a.Bitrate != b.Bitrate {
+		return a.Bitrate > b.Bitrate
+	}
+	return a.Default && !b.Default
+}
+
+//Position of the first preferred codec found in codecs, or len(preferred) if none is
+func codecRank(codecs string, preferred []string) int {
+	for rank, want := range preferred {
+		for _, codec := range strings.Split(codecs, ",") {
+			if strings.HasPrefix(strings.ToLower(strings.TrimSpace(codec)), strings.ToLower(want)) {
+				return rank
+			}
+		}
+	}
+	return len(preferred)
+}
+
+func typeRank(mediaType string, preferred []string) int {
+	for rank, want := range preferred {
+		if strings.EqualFold(mediaType, want) {
+			return rank
+		}
+	}
+	return len(preferred)
+}
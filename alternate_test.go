@@ -0,0 +1,146 @@
+package easyrss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+var alternateMedia = []byte("media bytes")
+
+func sri256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func sri384(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+var alternateFeed = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<item><title>1</title><enclosure url="https://e/a.mp3" type="audio/mpeg" length="100"/>
+<podcast:alternateEnclosure type="audio/mpeg" length="100" bitrate="128000" default="true" title="Standard"><podcast:source uri="https://e/a.mp3"/><podcast:source uri="ipfs://Qm"/></podcast:alternateEnclosure>
+<podcast:alternateEnclosure type="audio/opus" length="50" bitrate="64000" codecs="opus" lang="es"><podcast:source uri="ipfs://x"/><podcast:source uri="https://e/a.opus"/><podcast:integrity type="sri" value="` +
+	sri256(alternateMedia) + " " + sri384(alternateMedia) + `?opt"/></podcast:alternateEnclosure>
+<podcast:alternateEnclosure type="video/mp4" length="5000" bitrate="2000000" height="1080" codecs="avc1.64001F, mp4a.40.2"><podcast:source uri="https://e/a.mp4"/><podcast:source uri="magnet:?xt=1" contentType="application/x-bittorrent"/></podcast:alternateEnclosure>
+</item></channel></rss>`
+
+func checkAlternates(t *testing.T, r *RSS) {
+	t.Helper()
+	items, _ := r.Items()
+	item := &items[0]
+	alts, err := item.AlternateEnclosures()
+	if err != nil || len(alts) != 3 {
+		t.Fatalf("alternate enclosures = %+v, %v", alts, err)
+	}
+	want := []AlternateEnclosure{
+		{Type: "audio/mpeg", Length: 100, Bitrate: 128000, Title: "Standard", Default: true, Sources: []EnclosureSource{{URI: "https://e/a.mp3"}, {URI: "ipfs://Qm"}}},
+		{Type: "audio/opus", Length: 50, Bitrate: 64000, Codecs: "opus", Lang: "es", Sources: []EnclosureSource{{URI: "ipfs://x"}, {URI: "https://e/a.opus"}},
+			Integrity: &Integrity{Type: "sri", Value: sri256(alternateMedia) + " " + sri384(alternateMedia) + "?opt"}},
+		{Type: "video/mp4", Length: 5000, Bitrate: 2000000, Height: 1080, Codecs: "avc1.64001F, mp4a.40.2",
+			Sources: []EnclosureSource{{URI: "https://e/a.mp4"}, {URI: "magnet:?xt=1", ContentType: "application/x-bittorrent"}}},
+	}
+	if !reflect.DeepEqual(alts, want) {
+		t.Errorf("alternate enclosures = %+v, want %+v", alts, want)
+	}
+	if got := alts[1].HTTPSource(); got != "https://e/a.opus" {
+		t.Errorf("HTTPSource = %q", got)
+	}
+	tests := []struct {
+		name string
+		pref EnclosurePreference
+		want string //Type of the expected rendition
+	}{
+		{"best quality", EnclosurePreference{}, "video/mp4"},
+		{"codec", EnclosurePreference{Codecs: []string{"OPUS"}}, "audio/opus"},
+		{"codec prefix", EnclosurePreference{Codecs: []string{"mp4a"}}, "video/mp4"},
+		{"type", EnclosurePreference{Types: []string{"audio/mpeg"}}, "audio/mpeg"},
+		{"language", EnclosurePreference{Lang: "ES", MaxHeight: 720}, "audio/opus"},
+		{"bitrate limit", EnclosurePreference{MaxBitrate: 200000}, "audio/mpeg"},
+		{"height limit", EnclosurePreference{MaxHeight: 720}, "audio/mpeg"},
+		{"nothing within limits", EnclosurePreference{MaxBitrate: 1000}, "audio/opus"},
+	}
+	for _, tt := range tests {
+		if got, err := item.SelectEnclosure(tt.pref); err != nil || got.Type != tt.want {
+			t.Errorf("SelectEnclosure(%s) = %+v, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestAlternateEnclosures(t *testing.T) {
+	checkAllPaths(t, alternateFeed, checkAlternates)
+}
+
+//Numbers that can't be read are left at 0, and a rendition without an integrity tag can't be verified
+func TestAlternateEnclosuresMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<item><title>1</title><podcast:alternateEnclosure type="audio/mpeg" length="big" bitrate="fast" height="tall" default="yes"><podcast:source uri=" ipfs://Qm "/>
+</podcast:alternateEnclosure></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		alts, err := items[0].AlternateEnclosures()
+		want := []AlternateEnclosure{{Type: "audio/mpeg", Sources: []EnclosureSource{{URI: "ipfs://Qm"}}}}
+		if err != nil || !reflect.DeepEqual(alts, want) {
+			t.Fatalf("alternate enclosures = %+v, %v, want %+v", alts, err, want)
+		}
+		if got := alts[0].HTTPSource(); got != "" {
+			t.Errorf("HTTPSource = %q", got)
+		}
+		var fieldErr *FieldError
+		if err := alts[0].Verify(alternateMedia); !errors.As(err, &fieldErr) || fieldErr.Field != "integrity" {
+			t.Errorf("Verify error = %v", err)
+		}
+	})
+}
+
+func TestSelectPlainEnclosure(t *testing.T) {
+	var plain Item
+	plain.hasEnclosure, plain.enclosure.url = true, "http://x"
+	if e, err := plain.SelectEnclosure(EnclosurePreference{}); err != nil || e.HTTPSource() != "http://x" || !e.Default {
+		t.Errorf("SelectEnclosure = %+v, %v", e, err)
+	}
+	if _, err := (&Item{}).SelectEnclosure(EnclosurePreference{}); !errors.Is(err, ErrFieldNotPopulated) {
+		t.Errorf("item without media error = %v", err)
+	}
+}
+
+func TestVerifySRI(t *testing.T) {
+	tests := []struct {
+		name      string
+		integrity string
+		want      error
+	}{
+		{"sha256", sri256(alternateMedia), nil},
+		{"sha384 with options", sri384(alternateMedia) + "?opt", nil},
+		{"strongest wins", sri256(alternateMedia) + " sha384-AAAA", ErrIntegrityMismatch}, //A wrong sha384 fails even if sha256 matches
+		{"weaker ignored", "sha256-AAAA " + sri384(alternateMedia), nil},
+		{"mismatch", sri256([]byte("x")), ErrIntegrityMismatch},
+		{"unknown algorithm", "md5-xxx", ErrUnsupportedIntegrity},
+		{"empty", "", ErrUnsupportedIntegrity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySRI(alternateMedia, tt.integrity); !errors.Is(err, tt.want) || tt.want == nil && err != nil {
+				t.Errorf("VerifySRI = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	alt := AlternateEnclosure{Integrity: &Integrity{Type: "sri", Value: sri384(alternateMedia)}}
+	if err := alt.VerifyReader(bytes.NewReader(alternateMedia)); err != nil {
+		t.Errorf("VerifyReader = %v", err)
+	}
+	readErr := errors.New("connection reset")
+	if err := alt.VerifyReader(iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("VerifyReader on a failing reader = %v, want %v", err, readErr)
+	}
+	alt = AlternateEnclosure{Integrity: &Integrity{Type: "pgp-signature", Value: "sig"}}
+	if err := alt.Verify(alternateMedia); !errors.Is(err, ErrUnsupportedIntegrity) {
+		t.Errorf("pgp Verify = %v, want ErrUnsupportedIntegrity", err)
+	}
+}
@@ -0,0 +1,166 @@
+package easyrss
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Namespace of the markup inside Atom type="xhtml" text constructs
+const xhtmlNS = "http://www.w3.org/1999/xhtml"
+
+//Maps an Atom 1.0 <feed> onto the channel and its entries onto items
+func getAtomFeed(r *RSS, feed node) {
+	r.channel.isAtom = true
+	r.channel.language = feed.Attr("lang")
+	var entries []node
+	for activeElem := feed.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		if activeElem.Name() == "entry" && activeElem.Namespace() == atomNS {
+			entries = append(entries, activeElem)
+			continue
+		}
+		setAtomFeedField(r, activeElem)
+	}
+	r.channel.items = make([]Item, len(entries))
+	for itemID, entry := range entries {
+		getAtomEntry(&r.channel.items[itemID], entry)
+		r.channel.items[itemID].inheritAuthor(r.channel.atomAuthor)
+	}
+}
+
+//Sets Appropriate Channel Field Given a Child of the Atom Feed Node
+func setAtomFeedField(r *RSS, activeElem node) {
+	tag := activeElem.Name()
+	tagContent := activeElem.Content()
+	switch activeElem.Namespace() {
+	case itunesNS:
+		r.channel.isItunes = true
+		setItunesMetaField(activeElem, &r.channel.itunes)
+	case mrssNS:
+		r.channel.isMRSS = true
+		setMediaChannelMetaField(activeElem, &r.channel.media)
+	case podcastNS, podcastLegacyNS:
+		r.channel.isPodcast = true
+		setPodcastMetaField(activeElem, &r.channel.podcast)
+	case atomNS:
+		switch tag {
+		case "title":
+			r.channel.title = tagContent
+		case "subtitle":
+			r.channel.description = tagContent
+		case "link":
+			if rel := activeElem.Attr("rel"); rel == "" || rel == "alternate" {
+				r.channel.link = activeElem.Attr("href")
+			}
+		case "author":
+			r.channel.atomAuthor = atomAuthor(activeElem)
+		case "generator":
+			r.channel.generator = tagContent
+		case "rights":
+			r.channel.copyright = tagContent
+		case "category":
+			r.channel.categories = append(r.channel.categories, activeElem.Attr("term"))
+		case "updated":
+			r.channel.lastBuild = parseDate(tagContent)
+		case "logo":
+			r.channel.image.url = strings.TrimSpace(tagContent)
+		case "icon":
+			if r.channel.image.url == "" { //Logos are preferred, icons are often favicon sized
+				r.channel.image.url = strings.TrimSpace(tagContent)
+			}
+		}
+	}
+}
+
+//Sets item fields from an Atom <entry>. Summary is preferred for the description, falling back to content.
+func getAtomEntry(item *Item, e node) {
+	var published, updated string
+	for activeElem := e.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		tag := activeElem.Name()
+		tagContent := activeElem.Content()
+		switch activeElem.Namespace() {
+		case itunesNS:
+			item.isItunes = true
+			setItunesMetaField(activeElem, &item.itunes)
+		case mrssNS:
+			item.isMRSS = true
+			setMediaMetaField(activeElem, &item.media)
+		case podcastNS, podcastLegacyNS:
+			item.isPodcast = true
+			setPodcastMetaField(activeElem, &item.podcast)
+		case atomNS:
+			switch tag {
+			case "title":
+				item.title = tagContent
+			case "link":
+				switch activeElem.Attr("rel") {
+				case "", "alternate":
+					item.link = activeElem.Attr("href")
+				case "enclosure":
+					item.hasEnclosure = true
+					item.enclosure.url = activeElem.Attr("href")
+					item.enclosure.mediaType = activeElem.Attr("type")
+					item.enclosure.size, _ = strconv.ParseUint(activeElem.Attr("length"), 10, 64)
+				}
+			case "id":
+				item.guid = GUIDField{IsPermaLink: false, Content: tagContent}
+			case "published":
+				published = tagContent
+			case "updated":
+				updated = tagContent
+			case "summary":
+				item.description = atomText(activeElem)
+			case "content":
+				item.content = atomText(activeElem)
+			case "author":
+				item.author = atomAuthor(activeElem)
+			case "category":
+				item.categories = append(item.categories, Category{Domain: activeElem.Attr("scheme"), Value: activeElem.Attr("term")})
+			}
+		}
+	}
+	if item.description == "" {
+		item.description = item.content
+	}
+	if published != "" {
+		item.date = parseDate(published)
+	}
+	if item.date == nil && updated != "" {
+		item.date = parseDate(updated)
+	}
+}
+
+//Returns the name of an Atom person construct
+func atomAuthor(n node) string {
+	for authorElem := n.FirstChild(); authorElem != nil; authorElem = authorElem.NextSibling() {
+		if authorElem.Name() == "name" {
+			return authorElem.Content()
+		}
+	}
+	return ""
+}
+
+//Returns the value of an Atom text construct. XHTML is returned as markup without its wrapping div, so it can be used like the
+//HTML of other feeds. Text and HTML are returned as is.
+func atomText(n node) string {
+	if n.Attr("type") != "xhtml" {
+		return n.Content()
+	}
+	for div := n.FirstChild(); div != nil; div = div.NextSibling() {
+		if div.Name() == "div" && div.Namespace() == xhtmlNS {
+			return strings.TrimSpace(div.InnerXML())
+		}
+	}
+	return strings.TrimSpace(n.InnerXML())
+}
+
+//Entries without an author inherit the one of the feed, as required by RFC 4287
+func (i *Item) inheritAuthor(feedAuthor string) {
+	if i.author == "" {
+		i.author = feedAuthor
+	}
+}
+
+//Whether or not this feed was decoded from an Atom document
+func (r *RSS) IsAtom() bool {
+	return r.channel.isAtom
+}
@@ -0,0 +1,132 @@
+package easyrss
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en-gb">
+<title>Example Feed</title><subtitle>All about examples</subtitle>
+<link href="http://example.org/feed" rel="self"/><link href="http://example.org/"/>
+<updated>2003-12-13T18:30:02Z</updated><generator>gen</generator><rights>(c) Example</rights>
+<category term="news"/><logo>http://example.org/logo.png</logo><icon>http://example.org/favicon.ico</icon>
+<author><name>John Doe</name><email>john@example.org</email></author>
+<entry>
+ <title>Plain entry</title><link rel="alternate" href="http://example.org/1"/><link rel="related" href="http://other.org/"/>
+ <link rel="enclosure" href="http://example.org/1.mp3" type="audio/mpeg" length="1337"/>
+ <id>urn:uuid:1</id><published>2003-12-13T08:29:29-04:00</published><updated>2003-12-14T18:30:02Z</updated>
+ <summary>Some text.</summary><content type="html">&lt;p&gt;Full &lt;b&gt;text&lt;/b&gt;&lt;/p&gt;</content>
+ <author><name>Jane Roe</name></author><category term="tech" scheme="http://example.org/tags"/>
+</entry>
+<entry>
+ <title>XHTML entry</title><id>urn:uuid:2</id><updated>2003-12-15T18:30:02Z</updated>
+ <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Hello <b>world</b> &amp; <a href="http://example.org/?a=1&amp;b=2">more</a><br/></div></content>
+ <summary type="xhtml"><xhtml:div xmlns:xhtml="http://www.w3.org/1999/xhtml"><xhtml:p>Short <xhtml:em>summary</xhtml:em></xhtml:p></xhtml:div></summary>
+</entry>
+</feed>`
+
+func TestAtomFeed(t *testing.T) {
+	r, err := Decode([]byte(atomFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsAtom() {
+		t.Error("IsAtom() = false")
+	}
+	tests := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"title", r.Title, "Example Feed"},
+		{"description", r.Description, "All about examples"},
+		{"link", r.Link, "http://example.org/"},
+		{"language", r.Language, "en-gb"},
+		{"generator", r.Generator, "gen"},
+		{"copyright", r.Copyright, "(c) Example"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.get(); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if d, err := r.LastBuildDate(); err != nil || !d.Equal(time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC)) {
+		t.Errorf("updated = %v, %v", d, err)
+	}
+	if img, err := r.Image(); err != nil || img.url != "http://example.org/logo.png" {
+		t.Errorf("logo = %v, %v", img, err)
+	}
+}
+
+func TestAtomEntries(t *testing.T) {
+	r, err := Decode([]byte(atomFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := r.Items()
+	if err != nil || len(items) != 2 {
+		t.Fatal(items, err)
+	}
+	checkAtomEntries(t, items)
+}
+
+func TestAtomEntriesStreamed(t *testing.T) {
+	d, err := NewDecoder(bytes.NewReader([]byte(atomFeed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []Item
+	for {
+		item, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, *item)
+	}
+	checkAtomEntries(t, items)
+}
+
+func checkAtomEntries(t *testing.T, items []Item) {
+	t.Helper()
+	first, second := items[0], items[1]
+	tests := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"title", first.Title, "Plain entry"},
+		{"alternate link", first.Link, "http://example.org/1"},
+		{"summary", first.Description, "Some text."},
+		{"html content", first.Content, "<p>Full <b>text</b></p>"},
+		{"author", first.Author, "Jane Roe"},
+		{"inherited author", second.Author, "John Doe"},
+		{"xhtml content", second.Content, `Hello <b>world</b> &amp; <a href="http://example.org/?a=1&amp;b=2">more</a><br/>`},
+		{"prefixed xhtml summary", second.Description, "<p>Short <em>summary</em></p>"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.get(); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if g, _ := first.GUID(); g.Content != "urn:uuid:1" || g.IsPermaLink {
+		t.Errorf("id = %+v", g)
+	}
+	if d, _ := first.Date(); !d.Equal(time.Date(2003, 12, 13, 12, 29, 29, 0, time.UTC)) {
+		t.Errorf("published date = %v", d)
+	}
+	if d, _ := second.Date(); !d.Equal(time.Date(2003, 12, 15, 18, 30, 2, 0, time.UTC)) {
+		t.Errorf("updated date = %v", d)
+	}
+	if c, _ := first.Categories(); len(c) != 1 || c[0].Value != "tech" || c[0].Domain != "http://example.org/tags" {
+		t.Errorf("categories = %v", c)
+	}
+	if !first.HasEnclosure() || first.EnclosureURL() != "http://example.org/1.mp3" || first.enclosure.size != 1337 {
+		t.Errorf("enclosure = %+v", first.enclosure)
+	}
+}
@@ -0,0 +1,73 @@
+package easyrss
+
+import (
+	"bytes"
+)
+
+//Namespace of elements whose prefix is neither declared nor well known, so they aren't mistaken for core RSS elements
+const undeclaredNS = "urn:easyrss:undeclared"
+
+//Namespaces assumed for the extension prefixes feeds most often use without declaring them
+var wellKnownPrefixes = map[string]string{
+	"itunes":  itunesNS,
+	"media":   mrssNS,
+	"atom":    atomNS,
+	"content": contentNS,
+	"dc":      dcNS,
+	"podcast": podcastNS,
+}
+
+//Namespace an element gets when its prefix isn't declared in scope. Shared by every backend so they agree on sloppy feeds.
+func undeclaredPrefixNamespace(prefix string) string {
+	if uri, ok := wellKnownPrefixes[prefix]; ok {
+		return uri
+	}
+	return undeclaredNS
+}
+
+//A document node as seen by the decoders. Implemented by each parsing backend.
+type node interface {
+	Name() string       //Local name, without any namespace prefix
+	Namespace() string  //Namespace URI, or an empty string
+	Content() string    //Text content of the node and all its descendants
+	Attr(string) string //Attribute value by local name, or an empty string if missing
+	FirstChild() node   //First child element, or nil
+	NextSibling() node  //Next sibling element, or nil
+	InnerXML() string   //Markup of the children, text included. XHTML elements are written without their namespace prefix.
+}
+
+//A parsed document. Free must be called once the tree is no longer needed.
+type document interface {
+	Root() node
+	Free()
+}
+
+//Turns raw feed bytes into a document tree. The pure-Go backend is the default; build with the libxml2 tag to parse through libxml2
+//instead.
+type backend interface {
+	Parse(data []byte) (document, error)
+}
+
+//Error-tolerant parser with no cgo dependencies
+type pureBackend struct{}
+
+type pureDocument struct {
+	root *xmlNode
+}
+
+func (pureBackend) Parse(data []byte) (document, error) {
+	root, err := parseTree(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, ErrNotFeed
+	}
+	return pureDocument{root: root}, nil
+}
+
+func (d pureDocument) Root() node {
+	return d.root
+}
+
+func (d pureDocument) Free() {}
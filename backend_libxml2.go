@@ -0,0 +1,105 @@
+//go:build libxml2
+// +build libxml2
+
+package easyrss
+
+import (
+	"strings"
+
+	"github.com/moovweb/gokogiri"
+	"github.com/moovweb/gokogiri/xml"
+)
+
+var defaultBackend backend = libxml2Backend{}
+
+//Parses through libxml2 by way of the gokogiri bindings. Requires cgo and the libxml2 headers.
+type libxml2Backend struct{}
+
+type libxml2Document struct {
+	doc *xml.XmlDocument
+}
+
+//Adapts a gokogiri node to the node interface, skipping over text and comment nodes
+type libxml2Node struct {
+	n xml.Node
+}
+
+func (libxml2Backend) Parse(data []byte) (document, error) {
+	doc, err := gokogiri.ParseXml(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		doc.Free()
+		return nil, ErrNotFeed
+	}
+	return libxml2Document{doc: doc}, nil
+}
+
+func (d libxml2Document) Root() node {
+	return libxml2Node{d.doc.Root()}
+}
+
+func (d libxml2Document) Free() {
+	d.doc.Free()
+}
+
+//libxml2 keeps an element whose prefix isn't declared under its qualified name, with no namespace. Both are split here the way
+//the pure-Go backend resolves them.
+func (l libxml2Node) undeclaredPrefix() (prefix, local string, ok bool) {
+	if l.n.Namespace() != "" {
+		return "", "", false
+	}
+	name := l.n.Name()
+	colon := strings.IndexByte(name, ':')
+	if colon < 0 {
+		return "", "", false
+	}
+	return name[:colon], name[colon+1:], true
+}
+
+func (l libxml2Node) Name() string {
+	if _, local, ok := l.undeclaredPrefix(); ok {
+		return local
+	}
+	return l.n.Name()
+}
+
+func (l libxml2Node) Namespace() string {
+	if prefix, _, ok := l.undeclaredPrefix(); ok {
+		return undeclaredPrefixNamespace(prefix)
+	}
+	return l.n.Namespace()
+}
+
+func (l libxml2Node) Content() string {
+	return l.n.Content()
+}
+
+func (l libxml2Node) InnerXML() string {
+	return l.n.InnerHtml()
+}
+
+func (l libxml2Node) Attr(name string) string {
+	if attr := l.n.Attribute(name); attr != nil {
+		return attr.Value()
+	}
+	return ""
+}
+
+func (l libxml2Node) FirstChild() node {
+	return libxml2Element(l.n.FirstChild())
+}
+
+func (l libxml2Node) NextSibling() node {
+	return libxml2Element(l.n.NextSibling())
+}
+
+func libxml2Element(n xml.Node) node {
+	for ; n != nil; n = n.NextSibling() {
+		if n.NodeType() == xml.XML_ELEMENT_NODE {
+			return libxml2Node{n}
+		}
+	}
+	return nil
+}
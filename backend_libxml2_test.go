@@ -0,0 +1,62 @@
+//go:build libxml2
+// +build libxml2
+
+package easyrss
+
+import (
+	"reflect"
+	"testing"
+)
+
+//Everything the comparison looks at, beyond the channel title and item summaries the corpus spells out
+type backendSnapshot struct {
+	Title       string
+	Author      string
+	Items       []string
+	Authors     []string
+	ItunesTitle []string
+	Transcripts []int
+}
+
+func snapshotFeed(r *RSS) backendSnapshot {
+	var s backendSnapshot
+	s.Title, _ = r.Title()
+	s.Author, _ = r.ItunesAuthor()
+	s.Items = itemSummaries(r)
+	items, _ := r.Items()
+	for _, item := range items {
+		author, _ := item.ItunesAuthor()
+		itunesTitle, _ := item.ItunesTitle()
+		transcripts, _ := item.Transcripts()
+		s.Authors = append(s.Authors, author)
+		s.ItunesTitle = append(s.ItunesTitle, itunesTitle)
+		s.Transcripts = append(s.Transcripts, len(transcripts))
+	}
+	return s
+}
+
+func TestLibxml2MatchesPure(t *testing.T) {
+	for _, tt := range backendCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := decodeXML(pureBackend{}, []byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decodeXML(libxml2Backend{}, []byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g, w := snapshotFeed(got), snapshotFeed(want); !reflect.DeepEqual(g, w) {
+				t.Errorf("libxml2 = %+v\npure = %+v", g, w)
+			}
+		})
+	}
+}
+
+func TestLibxml2NotFeed(t *testing.T) {
+	for _, doc := range backendNotFeeds {
+		if _, err := decodeXML(libxml2Backend{}, []byte(doc)); err == nil {
+			t.Errorf("decoded %q", doc)
+		}
+	}
+}
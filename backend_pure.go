@@ -0,0 +1,6 @@
+//go:build !libxml2
+// +build !libxml2
+
+package easyrss
+
+var defaultBackend backend = pureBackend{}
@@ -0,0 +1,119 @@
+package easyrss
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+//Encodes a document as UTF-16 with a byte order mark
+func utf16Doc(doc string, order binary.ByteOrder) string {
+	units := utf16.Encode([]rune(doc))
+	b := make([]byte, 2*len(units)+2)
+	order.PutUint16(b, 0xfeff)
+	for u, unit := range units {
+		order.PutUint16(b[2+2*u:], unit)
+	}
+	return string(b)
+}
+
+//The decoding corpus every backend has to agree on
+var backendCorpus = []struct {
+	name  string
+	doc   string
+	title string   //Channel title
+	items []string //Title and description of each item, separated by "|"
+}{
+	{"rss 2.0", `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Main</title><link>http://example.org/</link>
+		<description>D</description><item><title>First</title><description>&lt;i&gt;x&lt;/i&gt;</description></item>
+		<item><title>Second</title><description>Plain</description></item></channel></rss>`,
+		"Main", []string{"First|<i>x</i>", "Second|Plain"}},
+	{"atom", `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title>
+		<entry><title>E1</title><summary>S1</summary></entry><entry><title>E2</title><content>C2</content></entry></feed>`,
+		"Atom", []string{"E1|S1", "E2|C2"}},
+	{"rss 1.0", `<?xml version="1.0"?><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+		<channel rdf:about="http://r/"><title>RDF</title><link>http://r/</link><description>D</description></channel>
+		<item rdf:about="http://r/1"><title>I1</title><description>D1</description></item></rdf:RDF>`,
+		"RDF", []string{"I1|D1"}},
+	{"namespaces", `<?xml version="1.0"?><rss version="2.0" xmlns:c="http://purl.org/rss/1.0/modules/content/"><channel>
+		<title>NS</title><item><title>T</title><c:encoded>full</c:encoded><description xmlns="">d</description></item></channel></rss>`,
+		"NS", []string{"T|d"}},
+	{"latin1", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><rss version=\"2.0\"><channel><title>Caf\xe9</title>" +
+		"<item><title>na\xefve</title><description>\xbd price</description></item></channel></rss>",
+		"Café", []string{"naïve|½ price"}},
+	{"windows-1252", "<?xml version=\"1.0\" encoding=\"windows-1252\"?><rss version=\"2.0\"><channel><title>\x93Quoted\x94</title>" +
+		"<item><title>a \x96 b</title><description>\x80 5</description></item></channel></rss>",
+		"“Quoted”", []string{"a – b|€ 5"}},
+	{"iso-8859-15", "<?xml version=\"1.0\" encoding=\"ISO-8859-15\"?><rss version=\"2.0\"><channel><title>\xa4 price</title>" +
+		"<item><title>\xbd</title><description>\xe9</description></item></channel></rss>",
+		"€ price", []string{"œ|é"}},
+	{"utf-16le", utf16Doc("<?xml version=\"1.0\" encoding=\"UTF-16\"?><rss version=\"2.0\"><channel><title>Grüße</title>"+
+		"<item><title>日本</title><description>😀 emoji</description></item></channel></rss>", binary.LittleEndian),
+		"Grüße", []string{"日本|😀 emoji"}},
+	{"utf-16be", utf16Doc("<?xml version=\"1.0\" encoding=\"UTF-16\"?><rss version=\"2.0\"><channel><title>Grüße</title>"+
+		"<item><title>日本</title><description>😀 emoji</description></item></channel></rss>", binary.BigEndian),
+		"Grüße", []string{"日本|😀 emoji"}},
+	{"utf-8 bom", "\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss version=\"2.0\"><channel><title>BOM</title>" +
+		"<item><title>ü</title><description>d</description></item></channel></rss>",
+		"BOM", []string{"ü|d"}},
+	{"entities", `<?xml version="1.0"?><rss version="2.0"><channel><title>Fish &amp; Chips</title>
+		<item><title>&lt;b&gt; &quot;q&quot; &apos;a&apos;</title><description>&#233;&#xE9;&#x263A;</description></item></channel></rss>`,
+		"Fish & Chips", []string{`<b> "q" 'a'|éé☺`}},
+	{"cdata", `<?xml version="1.0"?><rss version="2.0"><channel><title><![CDATA[A & B]]></title>
+		<item><title>T</title><description>before <![CDATA[<p>in & <b>out</b></p>]]> after</description></item></channel></rss>`,
+		"A & B", []string{"T|before <p>in & <b>out</b></p> after"}},
+	{"truncated", `<?xml version="1.0"?><rss version="2.0"><channel><title>Cut</title><item><title>A</title><description>B</description></item>
+		<item><title>C</title><description>D</description>`,
+		"Cut", []string{"A|B", "C|D"}},
+	{"undeclared prefixes", `<?xml version="1.0"?><rss version="2.0"><channel><title>Sloppy</title><foo:title>Wrong</foo:title>
+		<item><title>T</title><itunes:title>IT</itunes:title><foo:description>no</foo:description><description>d</description>
+		<itunes:author>A</itunes:author></item></channel></rss>`,
+		"Sloppy", []string{"T|d"}},
+}
+
+//Documents no backend may decode as a feed
+var backendNotFeeds = []string{`<?xml version="1.0"?><html><body>Not a feed</body></html>`, ``}
+
+//Summarizes the title and description of each item, separated by "|"
+func itemSummaries(r *RSS) []string {
+	items, _ := r.Items()
+	summaries := make([]string, len(items))
+	for itemID, item := range items {
+		itemTitle, _ := item.Title()
+		description, _ := item.Description()
+		summaries[itemID] = itemTitle + "|" + description
+	}
+	return summaries
+}
+
+//The corpus is checked against the pure-Go backend. Build with the libxml2 tag to compare libxml2 with it.
+func TestBackendCorpus(t *testing.T) {
+	for _, tt := range backendCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeXML(pureBackend{}, []byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := r.Title(); got != tt.title {
+				t.Errorf("title = %q, want %q", got, tt.title)
+			}
+			got := itemSummaries(r)
+			if len(got) != len(tt.items) {
+				t.Fatalf("%d items, want %d", len(got), len(tt.items))
+			}
+			for itemID, summary := range got {
+				if summary != tt.items[itemID] {
+					t.Errorf("item %d = %q, want %q", itemID, summary, tt.items[itemID])
+				}
+			}
+		})
+	}
+}
+
+func TestBackendNotFeed(t *testing.T) {
+	for _, doc := range backendNotFeeds {
+		if _, err := decodeXML(pureBackend{}, []byte(doc)); err == nil {
+			t.Errorf("decoded %q", doc)
+		}
+	}
+}
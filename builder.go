@@ -0,0 +1,690 @@
+package easyrss
+
+import (
+	"fmt"
+	"time"
+)
+
+//Builds a feed from scratch. Start with NewChannel, chain the With/Add setters and finish with Build.
+type ChannelBuilder struct {
+	channel Channel
+}
+
+//Builds a single feed item. Pass it to ChannelBuilder.AddItem once populated.
+type ItemBuilder struct {
+	item Item
+}
+
+//Builds Itunes metadata for either a channel or an item.
+type ItunesBuilder struct {
+	meta ItunesMeta
+}
+
+//Builds channel-wide MediaRSS metadata.
+type MediaChannelBuilder struct {
+	meta MediaChannelMeta
+}
+
+//Builds item-level MediaRSS metadata.
+type MediaBuilder struct {
+	meta MediaMeta
+}
+
+//Builds Podcasting 2.0 metadata for either a channel or an item.
+type PodcastBuilder struct {
+	meta PodcastMeta
+}
+
+//Returns an Image for use with the builders. Pass zero for unknown dimensions.
+func NewImage(url, title, link string, width, height int) Image {
+	return Image{url: url, title: title, link: link, width: width, height: height}
+}
+
+//Starts a new feed. Title, link and description are required by RSS 2.0 and are validated by Build.
+func NewChannel(title, link, description string) *ChannelBuilder {
+	return &ChannelBuilder{channel: Channel{title: title, link: link, description: description}}
+}
+
+//Sets the feed generator.
+func (b *ChannelBuilder) WithGenerator(generator string) *ChannelBuilder {
+	b.channel.generator = generator
+	return b
+}
+
+//Sets the feed language, e.g. "en-us".
+func (b *ChannelBuilder) WithLanguage(language string) *ChannelBuilder {
+	b.channel.language = language
+	return b
+}
+
+//Sets the feed copyright notice.
+func (b *ChannelBuilder) WithCopyright(copyright string) *ChannelBuilder {
+	b.channel.copyright = copyright
+	return b
+}
+
+//Adds a feed category. May be called multiple times.
+func (b *ChannelBuilder) AddCategory(category string) *ChannelBuilder {
+	b.channel.categories = append(b.channel.categories, category)
+	return b
+}
+
+//Sets the feed image, usually a logo shown next to the feed title.
+func (b *ChannelBuilder) WithImage(image Image) *ChannelBuilder {
+	b.channel.image = image
+	return b
+}
+
+//Sets the feed publication date and the last time its content changed. Pass nil to leave either out.
+func (b *ChannelBuilder) WithDates(pubDate, lastBuild *time.Time) *ChannelBuilder {
+	b.channel.pubDate = pubDate
+	b.channel.lastBuild = lastBuild
+	return b
+}
+
+//Sets how long the feed may be cached. Rounded down to whole minutes when encoded.
+func (b *ChannelBuilder) WithTTL(ttl time.Duration) *ChannelBuilder {
+	b.channel.ttl = ttl
+	return b
+}
+
+//Sets the managing editor and webmaster email addresses.
+func (b *ChannelBuilder) WithContacts(managingEditor, webMaster string) *ChannelBuilder {
+	b.channel.editor = managingEditor
+	b.channel.webMaster = webMaster
+	return b
+}
+
+//Sets the rssCloud endpoint for update notifications.
+func (b *ChannelBuilder) WithCloud(cloud Cloud) *ChannelBuilder {
+	b.channel.cloud = &cloud
+	return b
+}
+
+//Sets the hours and days during which aggregators shouldn't poll the feed.
+func (b *ChannelBuilder) WithSkip(hours HourSet, days DaySet) *ChannelBuilder {
+	b.channel.skipHours = hours
+	b.channel.skipDays = days
+	return b
+}
+
+//Attaches channel-wide Itunes metadata and marks the feed as an Itunes feed.
+func (b *ChannelBuilder) WithItunes(i *ItunesBuilder) *ChannelBuilder {
+	b.channel.itunes = i.meta
+	b.channel.isItunes = true
+	return b
+}
+
+//Attaches channel-wide MediaRSS metadata and marks the feed as a MediaRSS feed.
+func (b *ChannelBuilder) WithMedia(m *MediaChannelBuilder) *ChannelBuilder {
+	b.channel.media = m.meta
+	b.channel.isMRSS = true
+	return b
+}
+
+//Attaches channel-wide Podcasting 2.0 metadata and marks the feed as a Podcasting 2.0 feed.
+func (b *ChannelBuilder) WithPodcast(p *PodcastBuilder) *ChannelBuilder {
+	b.channel.podcast = p.meta
+	b.channel.isPodcast = true
+	return b
+}
+
+//Appends an item to the feed. The item is copied, so the ItemBuilder may be reused afterwards.
+func (b *ChannelBuilder) AddItem(i *ItemBuilder) *ChannelBuilder {
+	b.channel.items = append(b.channel.items, i.copyItem())
+	return b
+}
+
+//Appends a Podcasting 2.0 live item with the given status (LivePending, LiveLive or LiveEnded) and schedule. end may be nil. The
+//item is copied like in AddItem and validated by Build like any other item.
+func (b *ChannelBuilder) AddLiveItem(status string, start, end *time.Time, i *ItemBuilder) *ChannelBuilder {
+	live := LiveItem{Item: i.copyItem(), status: status}
+	if start != nil {
+		startCopy := *start
+		live.start = &startCopy
+	}
+	if end != nil {
+		endCopy := *end
+		live.end = &endCopy
+	}
+	b.channel.liveItems = append(b.channel.liveItems, live)
+	return b
+}
+
+//Validates the required RSS 2.0 elements and returns the finished feed. Channels need a title, link and description; items need at least a title or a description. Missing elements are reported as a *FieldError.
+func (b *ChannelBuilder) Build() (*RSS, error) {
+	if b.channel.title == "" {
+		return nil, notPopulated(ElementChannel, "", "title")
+	}
+	if b.channel.link == "" {
+		return nil, notPopulated(ElementChannel, "", "link")
+	}
+	if b.channel.description == "" {
+		return nil, notPopulated(ElementChannel, "", "description")
+	}
+	for itemID, item := range b.channel.items {
+		if item.title == "" && item.description == "" {
+			return nil, fmt.Errorf("Item %d requires a title or a description: %w", itemID, notPopulated(ElementItem, "", "title"))
+		}
+	}
+	for liveID, live := range b.channel.liveItems {
+		if live.title == "" && live.description == "" {
+			return nil, fmt.Errorf("Live item %d requires a title or a description: %w", liveID, notPopulated(ElementLiveItem, NamespacePodcast, "title"))
+		}
+	}
+	rssObj := RSS{channel: b.channel}
+	rssObj.channel.categories = append([]string(nil), b.channel.categories...)
+	rssObj.channel.items = append([]Item(nil), b.channel.items...)
+	rssObj.channel.liveItems = append([]LiveItem(nil), b.channel.liveItems...)
+	return &rssObj, nil
+}
+
+//Starts a new item. RSS 2.0 only requires one of title or description, the rest may be left empty.
+func NewItem(title, link, description string) *ItemBuilder {
+	return &ItemBuilder{item: Item{title: title, link: link, description: description}}
+}
+
+//Returns a copy of the item being built that shares nothing with the builder
+func (b *ItemBuilder) copyItem() Item {
+	item := b.item
+	item.categories = append([]Category(nil), b.item.categories...)
+	if b.item.source != nil {
+		source := *b.item.source
+		item.source = &source
+	}
+	if b.item.date != nil {
+		date := *b.item.date
+		item.date = &date
+	}
+	item.media = b.item.media.clone()
+	return item
+}
+
+//Sets the item publication time.
+func (b *ItemBuilder) WithDate(date time.Time) *ItemBuilder {
+	b.item.date = &date
+	return b
+}
+
+//Sets the full item content, usually HTML. Written out as content:encoded.
+func (b *ItemBuilder) WithContent(content string) *ItemBuilder {
+	b.item.content = content
+	return b
+}
+
+//Sets the item author.
+func (b *ItemBuilder) WithAuthor(author string) *ItemBuilder {
+	b.item.author = author
+	return b
+}
+
+//Adds an item category. Domain may be left empty.
+func (b *ItemBuilder) AddCategory(domain, category string) *ItemBuilder {
+	b.item.categories = append(b.item.categories, Category{Domain: domain, Value: category})
+	return b
+}
+
+//Sets the item GUID.
+func (b *ItemBuilder) WithGUID(guid string, isPermaLink bool) *ItemBuilder {
+	b.item.guid = GUIDField{IsPermaLink: isPermaLink, Content: guid}
+	return b
+}
+
+//Sets the URL of the item comments page.
+func (b *ItemBuilder) WithComments(comments string) *ItemBuilder {
+	b.item.comments = comments
+	return b
+}
+
+//Sets the channel the item was republished from.
+func (b *ItemBuilder) WithSource(url, title string) *ItemBuilder {
+	b.item.source = &Source{URL: url, Title: title}
+	return b
+}
+
+//Attaches a media enclosure. Size is in bytes.
+func (b *ItemBuilder) WithEnclosure(url, mediaType string, size uint64) *ItemBuilder {
+	b.item.enclosure = RSSEnclosure{url: url, mediaType: mediaType, size: size}
+	b.item.hasEnclosure = true
+	return b
+}
+
+//Attaches item-level Itunes metadata.
+func (b *ItemBuilder) WithItunes(i *ItunesBuilder) *ItemBuilder {
+	b.item.itunes = i.meta
+	b.item.isItunes = true
+	return b
+}
+
+//Attaches item-level MediaRSS metadata.
+func (b *ItemBuilder) WithMedia(m *MediaBuilder) *ItemBuilder {
+	b.item.media = m.meta.clone()
+	b.item.isMRSS = true
+	return b
+}
+
+//Attaches item-level Podcasting 2.0 metadata.
+func (b *ItemBuilder) WithPodcast(p *PodcastBuilder) *ItemBuilder {
+	b.item.podcast = p.meta
+	b.item.isPodcast = true
+	return b
+}
+
+//Starts a new set of Itunes metadata.
+func NewItunes() *ItunesBuilder {
+	return &ItunesBuilder{}
+}
+
+//Sets the Itunes "author" field.
+func (b *ItunesBuilder) WithAuthor(author string) *ItunesBuilder {
+	b.meta.author = author
+	return b
+}
+
+//Sets the Itunes "subtitle" field.
+func (b *ItunesBuilder) WithSubtitle(subtitle string) *ItunesBuilder {
+	b.meta.subtitle = subtitle
+	return b
+}
+
+//Sets the Itunes "summary" field.
+func (b *ItunesBuilder) WithSummary(summary string) *ItunesBuilder {
+	b.meta.summary = summary
+	return b
+}
+
+//Sets the Itunes "explicit" field. Usually "yes", "no" or "clean".
+func (b *ItunesBuilder) WithExplicit(explicit string) *ItunesBuilder {
+	b.meta.explicit = explicit
+	return b
+}
+
+//Sets the Itunes "keywords" field. Keywords are written as a comma separated list.
+func (b *ItunesBuilder) WithKeywords(keywords ...string) *ItunesBuilder {
+	b.meta.keywords = keywords
+	return b
+}
+
+//Sets the Itunes "image" url.
+func (b *ItunesBuilder) WithImage(url string) *ItunesBuilder {
+	b.meta.image.url = url
+	return b
+}
+
+//Sets the Itunes episode duration. Only meaningful for items.
+func (b *ItunesBuilder) WithDuration(duration time.Duration) *ItunesBuilder {
+	b.meta.duration = duration
+	return b
+}
+
+//Adds an Itunes category with optional subcategories. May be called multiple times. Only meaningful for channels.
+func (b *ItunesBuilder) AddCategory(category string, subcategories ...string) *ItunesBuilder {
+	c := ItunesCategory{Text: category}
+	for _, sub := range subcategories {
+		c.Subcategories = append(c.Subcategories, ItunesCategory{Text: sub})
+	}
+	b.meta.categories = append(b.meta.categories, c)
+	return b
+}
+
+//Sets the podcast owner. Only meaningful for channels.
+func (b *ItunesBuilder) WithOwner(name, email string) *ItunesBuilder {
+	b.meta.owner = &ItunesOwner{Name: name, Email: email}
+	return b
+}
+
+//Sets the Itunes "type" field, "episodic" or "serial". Only meaningful for channels.
+func (b *ItunesBuilder) WithType(podcastType string) *ItunesBuilder {
+	b.meta.podcastType = podcastType
+	return b
+}
+
+//Marks the podcast as complete. Only meaningful for channels.
+func (b *ItunesBuilder) WithComplete(complete bool) *ItunesBuilder {
+	b.meta.complete = complete
+	return b
+}
+
+//Sets the URL the podcast has moved to. Only meaningful for channels.
+func (b *ItunesBuilder) WithNewFeedURL(url string) *ItunesBuilder {
+	b.meta.newFeedURL = url
+	return b
+}
+
+//Sets the Itunes episode title, which shouldn't repeat the podcast title or episode number. Only meaningful for items.
+func (b *ItunesBuilder) WithTitle(title string) *ItunesBuilder {
+	b.meta.title = title
+	return b
+}
+
+//Sets the season and episode numbers and the episode type ("full", "trailer" or "bonus"). Pass zero or an empty string to leave a
+//field out. Only meaningful for items.
+func (b *ItunesBuilder) WithEpisode(season, episode int, episodeType string) *ItunesBuilder {
+	b.meta.season = season
+	b.meta.episode = episode
+	b.meta.episodeType = episodeType
+	return b
+}
+
+//Hides the podcast or episode from Apple Podcasts.
+func (b *ItunesBuilder) WithBlock(block bool) *ItunesBuilder {
+	b.meta.block = block
+	return b
+}
+
+//Starts a new set of channel-wide MediaRSS metadata.
+func NewMediaChannel() *MediaChannelBuilder {
+	return &MediaChannelBuilder{}
+}
+
+//Sets the MediaRSS feed age rating.
+func (b *MediaChannelBuilder) WithRating(rating string) *MediaChannelBuilder {
+	b.meta.rating = rating
+	return b
+}
+
+//Sets the MediaRSS feed copyright.
+func (b *MediaChannelBuilder) WithCopyright(copyright string) *MediaChannelBuilder {
+	b.meta.copyright = copyright
+	return b
+}
+
+//Sets the MediaRSS feed thumbnail.
+func (b *MediaChannelBuilder) WithThumbnail(thumbnail Image) *MediaChannelBuilder {
+	b.meta.thumbnail = thumbnail
+	return b
+}
+
+//Sets the MediaRSS feed keywords.
+func (b *MediaChannelBuilder) WithKeywords(keywords ...string) *MediaChannelBuilder {
+	b.meta.keywords = keywords
+	return b
+}
+
+//Adds a MediaRSS feed category. May be called multiple times.
+func (b *MediaChannelBuilder) AddCategory(category string) *MediaChannelBuilder {
+	b.meta.categories = append(b.meta.categories, category)
+	return b
+}
+
+//Restricts every item without restrictions of its own, e.g. AddRestriction("allow", "country", "us", "ca"). May be called multiple
+//times.
+func (b *MediaChannelBuilder) AddRestriction(relationship, restrictionType string, values ...string) *MediaChannelBuilder {
+	b.meta.restrictions = append(b.meta.restrictions, MediaRestriction{Relationship: relationship, Type: restrictionType, Values: append([]string(nil), values...)})
+	return b
+}
+
+//Starts a new set of item-level MediaRSS metadata.
+func NewMedia() *MediaBuilder {
+	return &MediaBuilder{}
+}
+
+//Sets a single MediaRSS content object, replacing any added before. Size is in bytes, pass zero if unknown.
+func (b *MediaBuilder) WithContent(url, mediaType string, size uint64) *MediaBuilder {
+	b.meta.contents = []MediaContent{{URL: url, Type: mediaType, FileSize: size, Expression: "full"}}
+	return b
+}
+
+//Adds a MediaRSS content object, e.g. one of several renditions. May be called multiple times.
+func (b *MediaBuilder) AddContent(content MediaContent) *MediaBuilder {
+	if content.Expression == "" {
+		content.Expression = "full"
+	}
+	b.meta.contents = append(b.meta.contents, content)
+	return b
+}
+
+//Adds a group of alternative renditions. Group-level details apply to every rendition that doesn't set its own. May be called
+//multiple times.
+func (b *MediaBuilder) AddGroup(details MediaDetails, renditions ...MediaContent) *MediaBuilder {
+	group := MediaGroup{MediaDetails: details}
+	for _, content := range renditions {
+		if content.Expression == "" {
+			content.Expression = "full"
+		}
+		group.Contents = append(group.Contents, content)
+	}
+	b.meta.groups = append(b.meta.groups, group)
+	return b
+}
+
+//Sets the MediaRSS item title.
+func (b *MediaBuilder) WithTitle(title string) *MediaBuilder {
+	b.meta.details.Title, b.meta.details.TitleType = title, "plain"
+	return b
+}
+
+//Sets the MediaRSS item description.
+func (b *MediaBuilder) WithDescription(description string) *MediaBuilder {
+	b.meta.details.Description, b.meta.details.DescriptionType = description, "plain"
+	return b
+}
+
+//Sets the MediaRSS item title as HTML rather than plain text.
+func (b *MediaBuilder) WithHTMLTitle(title string) *MediaBuilder {
+	b.meta.details.Title, b.meta.details.TitleType = title, "html"
+	return b
+}
+
+//Sets the MediaRSS item description as HTML rather than plain text.
+func (b *MediaBuilder) WithHTMLDescription(description string) *MediaBuilder {
+	b.meta.details.Description, b.meta.details.DescriptionType = description, "html"
+	return b
+}
+
+//Sets the MediaRSS item keywords.
+func (b *MediaBuilder) WithKeywords(keywords ...string) *MediaBuilder {
+	b.meta.details.Keywords = append([]string(nil), keywords...)
+	return b
+}
+
+//Sets a single MediaRSS item thumbnail, replacing any added before.
+func (b *MediaBuilder) WithThumbnail(thumbnail Image) *MediaBuilder {
+	b.meta.details.Thumbnails = []MediaThumbnail{{Image: thumbnail}}
+	return b
+}
+
+//Adds a MediaRSS item thumbnail taken at the given offset into the media. May be called multiple times.
+func (b *MediaBuilder) AddThumbnail(thumbnail Image, at time.Duration) *MediaBuilder {
+	b.meta.details.Thumbnails = append(b.meta.details.Thumbnails, MediaThumbnail{Image: thumbnail, Time: at})
+	return b
+}
+
+//Sets the page the media can be played in. Pass zero for unknown dimensions.
+func (b *MediaBuilder) WithPlayer(url string, width, height int) *MediaBuilder {
+	b.meta.details.Player = &MediaPlayer{URL: url, Width: width, Height: height}
+	return b
+}
+
+//Sets how to embed the media, e.g. WithEmbed(url, 512, 323, map[string]string{"type": "application/x-shockwave-flash"}).
+func (b *MediaBuilder) WithEmbed(url string, width, height int, params map[string]string) *MediaBuilder {
+	b.meta.details.Embed = &MediaEmbed{URL: url, Width: width, Height: height, Params: make(map[string]string, len(params))}
+	for name, value := range params {
+		b.meta.details.Embed.Params[name] = value
+	}
+	return b
+}
+
+//Adds a timed text segment. May be called multiple times.
+func (b *MediaBuilder) AddText(text MediaText) *MediaBuilder {
+	if text.Type == "" {
+		text.Type = "plain"
+	}
+	b.meta.details.Texts = append(b.meta.details.Texts, text)
+	return b
+}
+
+//Adds a subtitle link. May be called multiple times.
+func (b *MediaBuilder) AddSubTitle(url, mediaType, lang string) *MediaBuilder {
+	b.meta.details.SubTitles = append(b.meta.details.SubTitles, MediaSubTitle{URL: url, Type: mediaType, Lang: lang})
+	return b
+}
+
+//Credits someone for the media object, e.g. AddCredit("photographer", "Jane Doe"). May be called multiple times, including for
+//the same role.
+func (b *MediaBuilder) AddCredit(role, name string) *MediaBuilder {
+	b.meta.details.Credits = append(b.meta.details.Credits, MediaCredit{Role: role, Scheme: "urn:ebu", Name: name})
+	return b
+}
+
+//Same as AddCredit, but with a role from another vocabulary than urn:ebu, e.g. "urn:yvs".
+func (b *MediaBuilder) AddSchemeCredit(scheme, role, name string) *MediaBuilder {
+	b.meta.details.Credits = append(b.meta.details.Credits, MediaCredit{Role: role, Scheme: scheme, Name: name})
+	return b
+}
+
+//Adds an age rating, e.g. AddRating("urn:mpaa", "pg-13"). Pass an empty scheme for urn:simple. May be called multiple times.
+func (b *MediaBuilder) AddRating(scheme, value string) *MediaBuilder {
+	if scheme == "" {
+		scheme = "urn:simple"
+	}
+	b.meta.details.Ratings = append(b.meta.details.Ratings, MediaRating{Scheme: scheme, Value: value})
+	return b
+}
+
+//Restricts where the media may be played or shared, e.g. AddRestriction("deny", "country", "fr"). May be called multiple times.
+func (b *MediaBuilder) AddRestriction(relationship, restrictionType string, values ...string) *MediaBuilder {
+	b.meta.details.Restrictions = append(b.meta.details.Restrictions, MediaRestriction{Relationship: relationship, Type: restrictionType, Values: append([]string(nil), values...)})
+	return b
+}
+
+//Sets the media license. Type is the MIME type of the document at href.
+func (b *MediaBuilder) WithLicense(name, licenseType, href string) *MediaBuilder {
+	b.meta.details.License = &MediaLicense{Name: name, Type: licenseType, Href: href}
+	return b
+}
+
+//Sets the media copyright notice and, optionally, a link to the terms of use.
+func (b *MediaBuilder) WithCopyright(text, url string) *MediaBuilder {
+	b.meta.details.Copyright = &MediaCopyright{Text: text, URL: url}
+	return b
+}
+
+//Sets the media status: "active", "blocked" or "deleted".
+func (b *MediaBuilder) WithStatus(state, reason string) *MediaBuilder {
+	b.meta.details.Status = &MediaStatus{State: state, Reason: reason}
+	return b
+}
+
+//Adds a price. May be called multiple times.
+func (b *MediaBuilder) AddPrice(price MediaPrice) *MediaBuilder {
+	b.meta.details.Prices = append(b.meta.details.Prices, price)
+	return b
+}
+
+//Sets the media's ratings, statistics and tags.
+func (b *MediaBuilder) WithCommunity(community MediaCommunity) *MediaBuilder {
+	community.Tags = append([]MediaTag(nil), community.Tags...)
+	b.meta.details.Community = &community
+	return b
+}
+
+//Adds a hash of the media file as a hex string. Pass an empty algorithm for md5. May be called multiple times.
+func (b *MediaBuilder) AddHash(algorithm, value string) *MediaBuilder {
+	if algorithm == "" {
+		algorithm = "md5"
+	}
+	b.meta.details.Hashes = append(b.meta.details.Hashes, MediaHash{Algorithm: algorithm, Value: value})
+	return b
+}
+
+//Adds a peer-to-peer source, e.g. AddPeerLink("application/x-bittorrent", url). May be called multiple times.
+func (b *MediaBuilder) AddPeerLink(mediaType, url string) *MediaBuilder {
+	b.meta.details.PeerLinks = append(b.meta.details.PeerLinks, MediaPeerLink{Type: mediaType, URL: url})
+	return b
+}
+
+//Adds a location shown or mentioned in the media. May be called multiple times.
+func (b *MediaBuilder) AddLocation(location MediaLocation) *MediaBuilder {
+	b.meta.details.Locations = append(b.meta.details.Locations, location)
+	return b
+}
+
+//Adds a scene. May be called multiple times.
+func (b *MediaBuilder) AddScene(scene MediaScene) *MediaBuilder {
+	b.meta.details.Scenes = append(b.meta.details.Scenes, scene)
+	return b
+}
+
+//Starts a new set of Podcasting 2.0 metadata.
+func NewPodcast() *PodcastBuilder {
+	return &PodcastBuilder{}
+}
+
+//Adds a funding link. May be called multiple times. Only meaningful for channels.
+func (b *PodcastBuilder) AddFunding(url, text string) *PodcastBuilder {
+	b.meta.funding = append(b.meta.funding, Funding{URL: url, Text: text})
+	return b
+}
+
+//Adds a person involved with the podcast or episode. May be called multiple times.
+func (b *PodcastBuilder) AddPerson(person Person) *PodcastBuilder {
+	b.meta.persons = append(b.meta.persons, person)
+	return b
+}
+
+//Sets what the podcast or episode is about or where it was recorded.
+func (b *PodcastBuilder) WithLocation(location Location) *PodcastBuilder {
+	b.meta.location = &location
+	return b
+}
+
+//Sets the content license. url may be empty for SPDX identifiers.
+func (b *PodcastBuilder) WithLicense(identifier, url string) *PodcastBuilder {
+	b.meta.license = &License{Identifier: identifier, URL: url}
+	return b
+}
+
+//Sets whether other platforms may import the feed. Only meaningful for channels.
+func (b *PodcastBuilder) WithLocked(locked bool, owner string) *PodcastBuilder {
+	b.meta.locked = &Locked{Locked: locked, Owner: owner}
+	return b
+}
+
+//Sets the podcast GUID. Only meaningful for channels.
+func (b *PodcastBuilder) WithGUID(guid string) *PodcastBuilder {
+	b.meta.guid = guid
+	return b
+}
+
+//Sets the podcast medium, e.g. "podcast" or "music". Only meaningful for channels.
+func (b *PodcastBuilder) WithMedium(medium string) *PodcastBuilder {
+	b.meta.medium = medium
+	return b
+}
+
+//Adds a trailer. May be called multiple times. Only meaningful for channels.
+func (b *PodcastBuilder) AddTrailer(trailer Trailer) *PodcastBuilder {
+	b.meta.trailers = append(b.meta.trailers, trailer)
+	return b
+}
+
+//Adds a transcript link. Language and rel may be empty. May be called multiple times. Only meaningful for items.
+func (b *PodcastBuilder) AddTranscript(url, mediaType, language, rel string) *PodcastBuilder {
+	b.meta.transcripts = append(b.meta.transcripts, Transcript{URL: url, Type: mediaType, Language: language, Rel: rel})
+	return b
+}
+
+//Sets the chapters link. Only meaningful for items.
+func (b *PodcastBuilder) WithChapters(url, mediaType string) *PodcastBuilder {
+	b.meta.chapters = &ChaptersLink{URL: url, Type: mediaType}
+	return b
+}
+
+//Adds a value block. Check it with Value.Validate first. May be called multiple times, once per payment type.
+func (b *PodcastBuilder) AddValue(value Value) *PodcastBuilder {
+	b.meta.values = append(b.meta.values, value)
+	return b
+}
+
+//Adds somewhere other than the enclosure the item can be played. May be called multiple times. Only meaningful for items.
+func (b *PodcastBuilder) AddContentLink(href, text string) *PodcastBuilder {
+	b.meta.contentLinks = append(b.meta.contentLinks, ContentLink{Href: href, Text: text})
+	return b
+}
+
+//Adds a rendition of the item's media. May be called multiple times. Only meaningful for items.
+func (b *PodcastBuilder) AddAlternateEnclosure(alt AlternateEnclosure) *PodcastBuilder {
+	b.meta.alternateEnclosures = append(b.meta.alternateEnclosures, alt)
+	return b
+}
@@ -0,0 +1,154 @@
+package easyrss
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildValidation(t *testing.T) {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		builder *ChannelBuilder
+		element string
+		field   string
+	}{
+		{"no title", NewChannel("", "http://x", "D"), ElementChannel, "title"},
+		{"no link", NewChannel("T", "", "D"), ElementChannel, "link"},
+		{"no description", NewChannel("T", "http://x", ""), ElementChannel, "description"},
+		{"empty item", NewChannel("T", "http://x", "D").AddItem(NewItem("", "http://x/1", "")), ElementItem, "title"},
+		{"empty live item", NewChannel("T", "http://x", "D").AddLiveItem(LiveLive, &start, nil, NewItem("", "http://x/live", "")),
+			ElementLiveItem, "title"},
+		{"valid", NewChannel("T", "http://x", "D").AddItem(NewItem("", "", "only a description")).
+			AddLiveItem(LivePending, &start, nil, NewItem("Live", "", "")), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := tt.builder.Build()
+			if tt.field == "" {
+				if err != nil || r == nil {
+					t.Fatalf("Build() = %v, %v", r, err)
+				}
+				return
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) || !errors.Is(err, ErrFieldNotPopulated) {
+				t.Fatalf("Build() error = %v, want a *FieldError", err)
+			}
+			if fieldErr.Element != tt.element || fieldErr.Field != tt.field {
+				t.Errorf("Build() error on %s %s, want %s %s", fieldErr.Element, fieldErr.Field, tt.element, tt.field)
+			}
+		})
+	}
+}
+
+//Items are copied when added, so reusing or changing the builder afterwards doesn't touch the feed
+func TestBuilderCopiesItems(t *testing.T) {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	item := NewItem("First", "http://x/1", "D").AddCategory("", "a").WithSource("http://o/feed", "Other").WithDate(start)
+	channel := NewChannel("T", "http://x", "D").AddItem(item).AddLiveItem(LiveLive, &start, nil, item)
+	item.item.categories[0].Value = "changed"
+	item.item.source.Title = "changed"
+	*item.item.date = start.Add(time.Hour)
+	item.WithAuthor("changed")
+	start = start.Add(time.Hour)
+	r, err := channel.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := r.Items()
+	live, _ := r.LiveItems()
+	if len(items) != 1 || len(live) != 1 {
+		t.Fatalf("%d items and %d live items, want 1 and 1", len(items), len(live))
+	}
+	for name, got := range map[string]*Item{"item": &items[0], "live item": &live[0].Item} {
+		if c, _ := got.Categories(); c[0].Value != "a" {
+			t.Errorf("%s category = %q", name, c[0].Value)
+		}
+		if s, _ := got.Source(); s.Title != "Other" {
+			t.Errorf("%s source = %q", name, s.Title)
+		}
+		if _, err := got.Author(); err == nil {
+			t.Errorf("%s author set after it was added", name)
+		}
+		if d, _ := got.Date(); !d.Equal(time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s date = %v", name, d)
+		}
+	}
+	if s, _ := live[0].Start(); !s.Equal(time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("live item start = %v", s)
+	}
+}
+
+//MediaRSS metadata is copied all the way down, so one MediaBuilder can be reused for several items
+func TestBuilderCopiesMedia(t *testing.T) {
+	media := NewMedia().WithEmbed("http://x/e", 0, 0, map[string]string{"type": "a"}).AddRestriction("deny", "country", "fr").
+		WithCommunity(MediaCommunity{Tags: []MediaTag{{Name: "a", Weight: 1}}}).AddLocation(MediaLocation{Point: &GeoPoint{Lat: 1}}).
+		AddContent(MediaContent{URL: "http://x/1.mp4", MediaDetails: MediaDetails{Keywords: []string{"a"}}}).
+		AddGroup(MediaDetails{Credits: []MediaCredit{{Name: "a"}}}, MediaContent{URL: "http://x/2.mp4", MediaDetails: MediaDetails{Keywords: []string{"a"}}})
+	first := NewItem("First", "http://x/1", "D").WithMedia(media)
+	second := NewItem("Second", "http://x/2", "D").WithMedia(media)
+	media.meta.details.Embed.Params["type"] = "changed"
+	media.meta.details.Restrictions[0].Values[0] = "changed"
+	media.meta.details.Community.Tags[0].Name = "changed"
+	media.meta.details.Locations[0].Point.Lat = 2
+	media.meta.contents[0].Keywords[0] = "changed"
+	media.meta.groups[0].Credits[0].Name = "changed"
+	media.meta.groups[0].Contents[0].Keywords[0] = "changed"
+	r, err := NewChannel("T", "http://x", "D").AddItem(first).AddItem(second).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.item.media.groups[0].Contents[0].Keywords[0] = "changed"
+	items, _ := r.Items()
+	for _, item := range items {
+		d := item.media.details
+		if d.Embed.Params["type"] != "a" || d.Restrictions[0].Values[0] != "fr" || d.Community.Tags[0].Name != "a" || d.Locations[0].Point.Lat != 1 {
+			t.Errorf("%s item details = %+v", item.title, d)
+		}
+		if item.media.contents[0].Keywords[0] != "a" || item.media.groups[0].Credits[0].Name != "a" || item.media.groups[0].Contents[0].Keywords[0] != "a" {
+			t.Errorf("%s item contents = %+v, groups = %+v", item.title, item.media.contents, item.media.groups)
+		}
+	}
+}
+
+func TestBuilderRoundTrip(t *testing.T) {
+	date := time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)
+	r, err := NewChannel("Cast", "http://x", "D").WithLanguage("en").WithCopyright("(c) X").AddCategory("Tech").
+		WithItunes(NewItunes().WithAuthor("Bob").WithExplicit("no")).
+		AddItem(NewItem("Ep 1", "http://x/1", "First").WithDate(date).WithGUID("tag:1", false).WithAuthor("bob@x").
+			WithEnclosure("http://x/1.mp3", "audio/mpeg", 42).WithItunes(NewItunes().WithDuration(90 * time.Second))).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(out)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if a, err := decoded.ItunesAuthor(); err != nil || a != "Bob" {
+		t.Errorf("itunes author = %q, %v", a, err)
+	}
+	items, err := decoded.Items()
+	if err != nil || len(items) != 1 {
+		t.Fatal(items, err)
+	}
+	item := items[0]
+	if d, err := item.Date(); err != nil || !d.Equal(date) {
+		t.Errorf("date = %v, %v", d, err)
+	}
+	if g, _ := item.GUID(); g.Content != "tag:1" || g.IsPermaLink {
+		t.Errorf("guid = %+v", g)
+	}
+	if item.EnclosureURL() != "http://x/1.mp3" {
+		t.Errorf("enclosure = %q", item.EnclosureURL())
+	}
+	if d, err := item.ItunesDuration(); err != nil || *d != 90*time.Second {
+		t.Errorf("duration = %v, %v", d, err)
+	}
+}
@@ -0,0 +1,245 @@
+package easyrss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//rssCloud endpoint that can notify subscribers of channel updates.
+type Cloud struct {
+	Domain            string
+	Port              int
+	Path              string
+	RegisterProcedure string
+	Protocol          string //xml-rpc, soap or http-post
+}
+
+//A text input box that can be displayed with the channel.
+type TextInput struct {
+	Title       string //Label of the submit button
+	Description string
+	Name        string //Name of the text object
+	Link        string //URL of the script processing requests
+}
+
+//A set of hours of the day, 0 to 23 GMT, from <skipHours>.
+type HourSet uint32
+
+//A set of weekdays, from <skipDays>.
+type DaySet uint8
+
+//Whether hour is in the set.
+func (h HourSet) Contains(hour int) bool {
+	return hour >= 0 && hour < 24 && h&(1<<uint(hour)) != 0
+}
+
+//Returns the hours in the set in ascending order.
+func (h HourSet) Hours() []int {
+	var hours []int
+	for hour := 0; hour < 24; hour++ {
+		if h.Contains(hour) {
+			hours = append(hours, hour)
+		}
+	}
+	return hours
+}
+
+//Returns the set with hour added. Hours outside 0-23 are ignored, except 24 which some feeds use for midnight.
+func (h HourSet) Add(hour int) HourSet {
+	if hour == 24 {
+		hour = 0
+	}
+	if hour < 0 || hour > 23 {
+		return h
+	}
+	return h | 1<<uint(hour)
+}
+
+//Whether day is in the set.
+func (d DaySet) Contains(day time.Weekday) bool {
+	return day >= time.Sunday && day <= time.Saturday && d&(1<<uint(day)) != 0
+}
+
+//Returns the days in the set, starting with Sunday.
+func (d DaySet) Days() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if d.Contains(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+//Returns the set with day added.
+func (d DaySet) Add(day time.Weekday) DaySet {
+	if day < time.Sunday || day > time.Saturday {
+		return d
+	}
+	return d | 1<<uint(day)
+}
+
+func parseCloud(n node) *Cloud {
+	port, _ := strconv.Atoi(n.Attr("port"))
+	return &Cloud{
+		Domain:            n.Attr("domain"),
+		Port:              port,
+		Path:              n.Attr("path"),
+		RegisterProcedure: n.Attr("registerProcedure"),
+		Protocol:          n.Attr("protocol"),
+	}
+}
+
+//Parses a <textInput>. RSS 1.0 channels carry an empty reference to the real element, so fields found earlier are kept.
+func parseTextInput(n node, previous *TextInput) *TextInput {
+	t := TextInput{}
+	if previous != nil {
+		t = *previous
+	}
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		tagContent := strings.TrimSpace(activeElem.Content())
+		switch activeElem.Name() {
+		case "title":
+			t.Title = tagContent
+		case "description":
+			t.Description = tagContent
+		case "name":
+			t.Name = tagContent
+		case "link":
+			t.Link = tagContent
+		}
+	}
+	if t == (TextInput{}) {
+		return nil
+	}
+	return &t
+}
+
+func parseSkipHours(n node) HourSet {
+	var hours HourSet
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		if hour, err := strconv.Atoi(strings.TrimSpace(activeElem.Content())); err == nil && activeElem.Name() == "hour" {
+			hours = hours.Add(hour)
+		}
+	}
+	return hours
+}
+
+func parseSkipDays(n node) DaySet {
+	var days DaySet
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		if activeElem.Name() != "day" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(activeElem.Content()))
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.ToLower(day.String()) == name {
+				days = days.Add(day)
+			}
+		}
+	}
+	return days
+}
+
+//Returns the channel image. If the channel has no image, will return nil and an error.
+func (r *RSS) Image() (*Image, error) {
+	if r.channel.image.url == "" {
+		return nil, notPopulated(ElementChannel, "", "image")
+	}
+	return &r.channel.image, nil
+}
+
+//Returns the channel publication date. If the field is not populated or couldn't be parsed, will return nil and an error.
+func (r *RSS) PubDate() (*time.Time, error) {
+	if r.channel.pubDate == nil {
+		return nil, notPopulated(ElementChannel, "", "pubDate")
+	}
+	return r.channel.pubDate, nil
+}
+
+//Returns the last time the channel content changed. If the field is not populated or couldn't be parsed, will return nil and an error.
+func (r *RSS) LastBuildDate() (*time.Time, error) {
+	if r.channel.lastBuild == nil {
+		return nil, notPopulated(ElementChannel, "", "lastBuildDate")
+	}
+	return r.channel.lastBuild, nil
+}
+
+//Returns how long the channel may be cached before refreshing. If the field is not populated, will return 0 and an error.
+func (r *RSS) TTL() (time.Duration, error) {
+	if r.channel.ttl == 0 {
+		return 0, notPopulated(ElementChannel, "", "ttl")
+	}
+	return r.channel.ttl, nil
+}
+
+//Returns the email address of the person responsible for editorial content. If the field is not populated, will return an empty string and an error.
+func (r *RSS) ManagingEditor() (string, error) {
+	if r.channel.editor == "" {
+		return "", notPopulated(ElementChannel, "", "managingEditor")
+	}
+	return r.channel.editor, nil
+}
+
+//Returns the email address of the person responsible for technical issues. If the field is not populated, will return an empty string and an error.
+func (r *RSS) WebMaster() (string, error) {
+	if r.channel.webMaster == "" {
+		return "", notPopulated(ElementChannel, "", "webMaster")
+	}
+	return r.channel.webMaster, nil
+}
+
+//Returns the URL of the documentation for the feed format. If the field is not populated, will return an empty string and an error.
+func (r *RSS) Docs() (string, error) {
+	if r.channel.docs == "" {
+		return "", notPopulated(ElementChannel, "", "docs")
+	}
+	return r.channel.docs, nil
+}
+
+//Returns the rssCloud endpoint for update notifications. If the channel has no cloud element, will return nil and an error.
+func (r *RSS) Cloud() (*Cloud, error) {
+	if r.channel.cloud == nil {
+		return nil, notPopulated(ElementChannel, "", "cloud")
+	}
+	return r.channel.cloud, nil
+}
+
+//Returns the channel text input box. If the channel has no text input, will return nil and an error.
+func (r *RSS) TextInput() (*TextInput, error) {
+	if r.channel.textInput == nil {
+		return nil, notPopulated(ElementChannel, "", "textInput")
+	}
+	return r.channel.textInput, nil
+}
+
+//Returns the hours (GMT) during which aggregators shouldn't poll the feed. If the field is not populated, will return an empty set and an error.
+func (r *RSS) SkipHours() (HourSet, error) {
+	if r.channel.skipHours == 0 {
+		return 0, notPopulated(ElementChannel, "", "skipHours")
+	}
+	return r.channel.skipHours, nil
+}
+
+//Returns the days during which aggregators shouldn't poll the feed. If the field is not populated, will return an empty set and an error.
+func (r *RSS) SkipDays() (DaySet, error) {
+	if r.channel.skipDays == 0 {
+		return 0, notPopulated(ElementChannel, "", "skipDays")
+	}
+	return r.channel.skipDays, nil
+}
+
+//Whether the feed asks not to be polled at t, according to its skipHours and skipDays.
+func (r *RSS) ShouldSkip(t time.Time) bool {
+	t = t.UTC()
+	return r.channel.skipHours.Contains(t.Hour()) || r.channel.skipDays.Contains(t.Weekday())
+}
+
+//Returns the channel PICS rating. If the field is not populated, will return an empty string and an error.
+func (r *RSS) Rating() (string, error) {
+	if r.channel.rating == "" {
+		return "", notPopulated(ElementChannel, "", "rating")
+	}
+	return r.channel.rating, nil
+}
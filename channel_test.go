@@ -0,0 +1,161 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const channelFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><link>http://x</link><description>D</description>
+<image><url>http://x/i.png</url><title>I</title><link>http://x</link><width>88</width><height>31</height><description>desc</description></image>
+<pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate><lastBuildDate>Tue, 03 Jan 2006 15:04:05 -0700</lastBuildDate><ttl>60</ttl>
+<managingEditor>ed@x</managingEditor><webMaster>wm@x</webMaster><docs>http://d</docs>
+<cloud domain="rpc.x" port="80" path="/RPC2" registerProcedure="p.r" protocol="xml-rpc"/>
+<textInput><title>Go</title><description>Search</description><name>q</name><link>http://x/s</link></textInput>
+<skipHours><hour>0</hour><hour>24</hour><hour>5</hour></skipHours><skipDays><day>Saturday</day><day>sunday</day><day>Someday</day></skipDays>
+<rating>pics</rating><item><title>a</title></item></channel></rss>`
+
+func checkChannel(t *testing.T, r *RSS) {
+	t.Helper()
+	pubDate := time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)
+	lastBuild := time.Date(2006, 1, 3, 22, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"image", func() (interface{}, error) {
+			img, err := r.Image()
+			return *img, err
+		}, Image{url: "http://x/i.png", title: "I", link: "http://x", width: 88, height: 31, description: "desc"}},
+		{"pubDate", func() (interface{}, error) { d, err := r.PubDate(); return d.UTC(), err }, pubDate},
+		{"lastBuildDate", func() (interface{}, error) { d, err := r.LastBuildDate(); return d.UTC(), err }, lastBuild},
+		{"ttl", func() (interface{}, error) { return r.TTL() }, time.Hour},
+		{"managingEditor", func() (interface{}, error) { return r.ManagingEditor() }, "ed@x"},
+		{"webMaster", func() (interface{}, error) { return r.WebMaster() }, "wm@x"},
+		{"docs", func() (interface{}, error) { return r.Docs() }, "http://d"},
+		{"cloud", func() (interface{}, error) {
+			c, err := r.Cloud()
+			return *c, err
+		}, Cloud{Domain: "rpc.x", Port: 80, Path: "/RPC2", RegisterProcedure: "p.r", Protocol: "xml-rpc"}},
+		{"textInput", func() (interface{}, error) {
+			ti, err := r.TextInput()
+			return *ti, err
+		}, TextInput{Title: "Go", Description: "Search", Name: "q", Link: "http://x/s"}},
+		{"skipHours", func() (interface{}, error) { h, err := r.SkipHours(); return h.Hours(), err }, []int{0, 5}},
+		{"skipDays", func() (interface{}, error) { d, err := r.SkipDays(); return d.Days(), err }, []time.Weekday{time.Sunday, time.Saturday}},
+		{"rating", func() (interface{}, error) { return r.Rating() }, "pics"},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestChannelElements(t *testing.T) {
+	checkAllPaths(t, channelFeed, checkChannel)
+}
+
+func TestChannelElementsMissing(t *testing.T) {
+	r, err := Decode([]byte(encodePlainFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]func() error{
+		"image":          func() error { _, err := r.Image(); return err },
+		"pubDate":        func() error { _, err := r.PubDate(); return err },
+		"lastBuildDate":  func() error { _, err := r.LastBuildDate(); return err },
+		"ttl":            func() error { _, err := r.TTL(); return err },
+		"managingEditor": func() error { _, err := r.ManagingEditor(); return err },
+		"cloud":          func() error { _, err := r.Cloud(); return err },
+		"textInput":      func() error { _, err := r.TextInput(); return err },
+		"skipHours":      func() error { _, err := r.SkipHours(); return err },
+		"skipDays":       func() error { _, err := r.SkipDays(); return err },
+		"rating":         func() error { _, err := r.Rating(); return err },
+	}
+	for name, get := range tests {
+		if err := get(); !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("%s error = %v, want ErrFieldNotPopulated", name, err)
+		}
+	}
+}
+
+//Values that can't be read leave their field unpopulated rather than failing the whole feed
+func TestChannelElementsMalformed(t *testing.T) {
+	const doc = `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><link>http://x</link><description>D</description>
+<image><url>http://x/i.png</url><width>wide</width><height>-</height></image><pubDate>someday</pubDate><ttl>soon</ttl>
+<cloud domain="rpc.x" port="eighty"/><skipHours><hour>noon</hour><hour>25</hour></skipHours><skipDays><day>Caturday</day></skipDays>
+<item><title>a</title></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		img, err := r.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := img.Width(); !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("width error = %v", err)
+		}
+		if _, err := img.Height(); !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("height error = %v", err)
+		}
+		if c, err := r.Cloud(); err != nil || c.Domain != "rpc.x" || c.Port != 0 {
+			t.Errorf("cloud = %+v, %v", c, err)
+		}
+		tests := map[string]func() error{
+			"pubDate":   func() error { _, err := r.PubDate(); return err },
+			"ttl":       func() error { _, err := r.TTL(); return err },
+			"skipHours": func() error { _, err := r.SkipHours(); return err },
+			"skipDays":  func() error { _, err := r.SkipDays(); return err },
+		}
+		for name, get := range tests {
+			if err := get(); !errors.Is(err, ErrFieldNotPopulated) {
+				t.Errorf("%s error = %v, want ErrFieldNotPopulated", name, err)
+			}
+		}
+	})
+}
+
+func TestShouldSkip(t *testing.T) {
+	r, err := Decode([]byte(channelFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2024, 1, 1, 5, 30, 0, 0, time.UTC), true},                   //Monday, skipped hour
+		{time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC), false},                   //Monday
+		{time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC), true},                   //Hour 24 means midnight
+		{time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), true},                   //Saturday
+		{time.Date(2024, 1, 1, 7, 30, 0, 0, time.FixedZone("", 2*3600)), true}, //05:30 GMT
+	}
+	for _, tt := range tests {
+		if got := r.ShouldSkip(tt.at); got != tt.want {
+			t.Errorf("ShouldSkip(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+//RSS 1.0 links the image and text input from the channel and describes them at the top level
+func TestRDFImageTextInput(t *testing.T) {
+	const doc = `<?xml version="1.0"?><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+<channel rdf:about="http://x"><title>T</title><link>http://x</link><description>D</description><image rdf:resource="http://x/i.png"/><textinput rdf:resource="http://x/s"/></channel>
+<image rdf:about="http://x/i.png"><title>I</title><url>http://x/i.png</url><link>http://x</link></image>
+<item rdf:about="http://x/1"><title>a</title><link>http://x/1</link></item>
+<textinput rdf:about="http://x/s"><title>Go</title><description>S</description><name>q</name><link>http://x/s</link></textinput></rdf:RDF>`
+	full, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, r := range map[string]*RSS{"decode": full, "stream": streamFeed(t, doc)} {
+		if img, err := r.Image(); err != nil || img.title != "I" || img.url != "http://x/i.png" {
+			t.Errorf("%s image = %+v, %v", name, img, err)
+		}
+		if ti, err := r.TextInput(); err != nil || ti.Name != "q" {
+			t.Errorf("%s text input = %+v, %v", name, ti, err)
+		}
+	}
+}
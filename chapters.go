@@ -0,0 +1,68 @@
+package easyrss
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+//A chapter marker from a Podcasting 2.0 JSON chapters file
+type Chapter struct {
+	Start time.Duration
+	End   time.Duration //Start of the next chapter when the file doesn't say, zero for the last one
+	Title string
+	Image string //Chapter artwork URL
+	URL   string //Web page related to the chapter
+	TOC   bool   //Whether the chapter belongs in the table of contents. Silent markers such as artwork changes don't.
+}
+
+type jsonChapter struct {
+	StartTime float64  `json:"startTime"`
+	EndTime   *float64 `json:"endTime"`
+	Title     string   `json:"title"`
+	Img       string   `json:"img"`
+	URL       string   `json:"url"`
+	TOC       *bool    `json:"toc"`
+}
+
+//Parses a Podcasting 2.0 JSON chapters file. Chapters are returned in start order.
+func ParseChapters(data []byte) ([]Chapter, error) {
+	var file struct {
+		Chapters []jsonChapter `json:"chapters"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Chapters == nil {
+		return nil, fmt.Errorf("Missing chapters array: %w", ErrBadChapters)
+	}
+	chapters := make([]Chapter, 0, len(file.Chapters))
+	for _, c := range file.Chapters {
+		chapter := Chapter{
+			Start: secondsToDuration(c.StartTime),
+			Title: c.Title,
+			Image: c.Img,
+			URL:   c.URL,
+			TOC:   c.TOC == nil || *c.TOC, //Defaults to true
+		}
+		if c.EndTime != nil {
+			chapter.End = secondsToDuration(*c.EndTime)
+		}
+		chapters = append(chapters, chapter)
+	}
+	sort.SliceStable(chapters, func(a, b int) bool {
+		return chapters[a].Start < chapters[b].Start
+	})
+	for c := 0; c+1 < len(chapters); c++ {
+		if chapters[c].End == 0 {
+			chapters[c].End = chapters[c+1].Start
+		}
+	}
+	return chapters, nil
+}
+
+//Parses a downloaded chapters file.
+func (c ChaptersLink) Parse(data []byte) ([]Chapter, error) {
+	return ParseChapters(data)
+}
@@ -0,0 +1,313 @@
+package easyrss
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Layouts tried, in order, once a feed date has been normalized: weekday dropped, commas removed, whitespace collapsed and
+//timezone names turned into numeric offsets
+var dateLayouts = []string{
+	"2 Jan 2006 15:04:05 -0700", //RFC 1123 and RFC 822, as required by RSS
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+	"2-Jan-06 15:04:05 -0700", //RFC 850
+	"2-Jan-2006 15:04:05 -0700",
+	"2006-01-02T15:04:05Z07:00", //RFC 3339 and ISO 8601
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04-0700",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -0700", //Also what time.Time.String writes
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04 -0700",
+	"2006-01-02 15:04",
+	"2006-01-02 -0700",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"20060102T150405",
+	"20060102",
+	"Jan 2 15:04:05 2006",       //ANSIC
+	"Jan 2 15:04:05 -0700 2006", //Unix and Ruby dates
+	"Jan 2 2006 15:04:05 -0700",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006",
+	"January 2 2006 15:04:05 -0700",
+	"January 2 2006 15:04:05",
+	"January 2 2006",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04:05",
+	"2 January 2006",
+	"2006 Jan 2 15:04:05 -0700", //Year first, as in Hungarian
+	"2006 Jan 2 15:04:05",
+	"2006 Jan 2 15:04",
+	"2006 Jan 2",
+	"2.1.2006 15:04:05 -0700", //Day first with dots, common across Europe
+	"2.1.2006 15:04:05",
+	"2.1.2006 15:04 -0700",
+	"2.1.2006 15:04",
+	"2.1.2006",
+	"2 Jan 2006 3:04:05 PM -0700", //12-hour clocks
+	"2 Jan 2006 3:04 PM -0700",
+	"2 Jan 2006 3:04:05 PM",
+	"2 Jan 2006 3:04 PM",
+	"Jan 2 2006 3:04:05 PM -0700",
+	"Jan 2 2006 3:04 PM -0700",
+	"Jan 2 2006 3:04:05 PM",
+	"Jan 2 2006 3:04 PM",
+	"January 2 2006 3:04:05 PM -0700",
+	"January 2 2006 3:04 PM -0700",
+	"January 2 2006 3:04 PM",
+	"2006-01-02 3:04:05 PM -0700",
+	"2006-01-02 3:04:05 PM",
+	"2006-01-02 3:04 PM",
+}
+
+//Offsets, in minutes east of UTC, of the timezone names found in feed dates. Names shared by several zones resolve to the one feeds
+//most likely mean, e.g. IST is India rather than Ireland or Israel. Use RegisterTimezone to override them.
+var timezoneOffsets = map[string]int{
+	"UT": 0, "UTC": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"EST": -5 * 60, "EDT": -4 * 60, "CST": -6 * 60, "CDT": -5 * 60, "MST": -7 * 60, "MDT": -6 * 60, "PST": -8 * 60, "PDT": -7 * 60,
+	"AKST": -9 * 60, "AKDT": -8 * 60, "HST": -10 * 60, "AST": -4 * 60, "ADT": -3 * 60, "NST": -(3*60 + 30), "NDT": -(2*60 + 30),
+	"BST": 60, "IST": 5*60 + 30, "CET": 60, "CEST": 2 * 60, "MET": 60, "MEST": 2 * 60, "WEST": 60, "EET": 2 * 60, "EEST": 3 * 60,
+	"MSK": 3 * 60, "HKT": 8 * 60, "SGT": 8 * 60, "AWST": 8 * 60, "JST": 9 * 60, "KST": 9 * 60, "ACST": 9*60 + 30,
+	"ACDT": 10*60 + 30, "AEST": 10 * 60, "AEDT": 11 * 60, "NZST": 12 * 60, "NZDT": 13 * 60,
+}
+
+var weekdayNames = map[string]bool{
+	"mon": true, "tue": true, "tues": true, "wed": true, "thu": true, "thur": true, "thurs": true, "fri": true, "sat": true, "sun": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+}
+
+var isoWeekDate = regexp.MustCompile(`^(\d{4})-?W(0[1-9]|[1-4]\d|5[0-3])(?:-?([1-7]))?(T.*)?$`)
+var dottedDate = regexp.MustCompile(`^\d{1,2}\.\d{1,2}\.\d{4}$`) //"14.03.2023", after which "15.04" is a time
+var offsetToken = regexp.MustCompile(`^(?:GMT|UTC|UT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+//Layouts and timezones added at runtime
+var dateRegistry struct {
+	sync.RWMutex
+	layouts   []string
+	timezones map[string]int
+}
+
+//Index into dateLayouts of the last layout that matched. A feed writes all its dates the same way, so it is tried first.
+var lastDateLayout int32
+
+//Adds a layout, in time.Parse format, to try before the built-in ones. Registered layouts are matched against the date exactly as
+//written in the feed, before any normalization. Safe for concurrent use.
+func RegisterDateLayout(layout string) {
+	dateRegistry.Lock()
+	defer dateRegistry.Unlock()
+	dateRegistry.layouts = append(dateRegistry.layouts, layout)
+}
+
+//Sets the offset from UTC of a timezone name, overriding the built-in table, e.g. RegisterTimezone("IST", time.Hour) for a feed
+//from Ireland. Safe for concurrent use.
+func RegisterTimezone(name string, offset time.Duration) {
+	dateRegistry.Lock()
+	defer dateRegistry.Unlock()
+	if dateRegistry.timezones == nil {
+		dateRegistry.timezones = make(map[string]int)
+	}
+	dateRegistry.timezones[strings.ToUpper(name)] = int(offset / time.Minute)
+}
+
+//Parses a date the way every date in a feed is parsed. RFC 822, RFC 1123, RFC 850, RFC 3339, ISO 8601 (including week dates) and
+//a number of common variations are understood, with or without weekday, seconds or timezone, on 24 or 12-hour clocks. Timezone
+//names such as EST are resolved to their actual offset, and an explicit offset after the name wins. Dates without a timezone are
+//taken as UTC. Dates in other languages are tried last, see ParseDateIn. Returns an error wrapping ErrBadDate if the date isn't
+//in any known format.
+func ParseDate(value string) (time.Time, error) {
+	return ParseDateIn(value, "")
+}
+
+//Parses a date written in English or a standard numeric format
+func parseStandardDate(value string) (time.Time, bool) {
+	dateRegistry.RLock()
+	for _, layout := range dateRegistry.layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			dateRegistry.RUnlock()
+			return parsed, true
+		}
+	}
+	dateRegistry.RUnlock()
+
+	normalized := normalizeDate(value)
+	cached := atomic.LoadInt32(&lastDateLayout)
+	if parsed, err := time.Parse(dateLayouts[cached], normalized); err == nil {
+		return parsed, true
+	}
+	for l, layout := range dateLayouts {
+		if int32(l) == cached {
+			continue
+		}
+		if parsed, err := time.Parse(layout, normalized); err == nil {
+			atomic.StoreInt32(&lastDateLayout, int32(l))
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+//Parses a feed date. Returns nil if it isn't in any known format.
+func parseDate(content string) *time.Time {
+	if parsed, err := ParseDate(content); err == nil {
+		return &parsed
+	}
+	return nil
+}
+
+//Rewrites a date into the shapes dateLayouts expects
+func normalizeDate(value string) string {
+	if comment := strings.IndexByte(value, '('); comment > 0 && strings.HasSuffix(value, ")") { //e.g. "+0000 (UTC)"
+		value = value[:comment]
+	}
+	if week := isoWeekDate.FindStringSubmatch(value); week != nil {
+		value = isoWeekToDate(week[1], week[2], week[3]) + week[4]
+	}
+	value = strings.Map(func(r rune) rune {
+		if r == ',' {
+			return ' '
+		}
+		return r
+	}, decimalComma(value))
+
+	tokens := strings.Fields(value)
+	if len(tokens) > 0 && weekdayNames[strings.ToLower(strings.TrimRight(tokens[0], "."))] {
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 0 && len(tokens[0]) == 10 && tokens[0][4] == '/' && tokens[0][7] == '/' { //2006/01/02
+		tokens[0] = strings.Replace(tokens[0], "/", "-", 2)
+	}
+	normalized := make([]string, 0, len(tokens)+1) //Not tokens[:0], splitting "3:04PM" adds a token
+	seenTime := false
+	offsetAt, offsetNamed := -1, false //Where the offset went in normalized, and whether it came from a timezone name
+	for _, token := range tokens {
+		if !seenTime {
+			if len(normalized) > 0 && dottedDate.MatchString(normalized[len(normalized)-1]) && dottedClock.MatchString(token) {
+				token = strings.Replace(token, ".", ":", 1)
+			}
+			if colon := strings.IndexByte(token, ':'); colon >= 0 {
+				seenTime = true
+				if colon == 1 { //Single digit hour
+					token = "0" + token
+				}
+				if clock, meridiem := splitMeridiem(token); meridiem != "" { //"03:04PM"
+					normalized = append(normalized, clock, meridiem)
+					continue
+				}
+			}
+			normalized = append(normalized, token)
+			continue
+		}
+		offset, isOffset := zoneOffset(token)
+		switch {
+		case isOffset && offsetAt >= 0:
+			if offsetNamed && !isLetters(token) { //An explicit offset beats the zone name before it, e.g. "GMT +0100"
+				normalized[offsetAt], offsetNamed = offset, false
+			} //Otherwise a name repeating the numeric offset, e.g. "-0700 MST"
+		case isOffset:
+			offsetAt, offsetNamed = len(normalized), isLetters(token)
+			normalized = append(normalized, offset)
+		case strings.EqualFold(token, "AM") || strings.EqualFold(token, "PM"):
+			normalized = append(normalized, strings.ToUpper(token))
+		case isLetters(token): //Unknown timezone name, taken as UTC
+		default:
+			normalized = append(normalized, token)
+		}
+	}
+	return strings.Join(normalized, " ")
+}
+
+//Turns commas after a seconds field into decimal points, as ISO 8601 allows "15:04:05,123". Other commas, e.g. in "Mar 14,2023",
+//are left alone.
+func decimalComma(value string) string {
+	b := []byte(value)
+	for c := 3; c+1 < len(b); c++ {
+		if b[c] == ',' && b[c-3] == ':' && isDigit(b[c-2]) && isDigit(b[c-1]) && isDigit(b[c+1]) {
+			b[c] = '.'
+		}
+	}
+	return string(b)
+}
+
+//Returns token as a "-0700" style offset if it is a timezone name or offset
+func zoneOffset(token string) (string, bool) {
+	token = strings.ToUpper(token)
+	dateRegistry.RLock()
+	minutes, ok := dateRegistry.timezones[token]
+	dateRegistry.RUnlock()
+	if !ok {
+		minutes, ok = timezoneOffsets[token]
+	}
+	if !ok {
+		match := offsetToken.FindStringSubmatch(token)
+		if match == nil {
+			return "", false
+		}
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ = strconv.Atoi(match[3])
+		if hours > 14 || minutes > 59 {
+			return "", false
+		}
+		minutes += hours * 60
+		if match[1] == "-" {
+			minutes = -minutes
+		}
+	}
+	sign := "+"
+	if minutes < 0 {
+		sign, minutes = "-", -minutes
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, minutes/60, minutes%60), true
+}
+
+//Splits a clock written without a space before AM or PM, returning an empty meridiem if there is none
+func splitMeridiem(token string) (string, string) {
+	if len(token) < 3 {
+		return token, ""
+	}
+	meridiem := strings.ToUpper(token[len(token)-2:])
+	if meridiem != "AM" && meridiem != "PM" {
+		return token, ""
+	}
+	return token[:len(token)-2], meridiem
+}
+
+func isLetters(token string) bool {
+	for _, r := range token {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return token != ""
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+//Converts an ISO 8601 week date to a calendar date. Week 1 is the week with the year's first Thursday, weeks start on Monday.
+func isoWeekToDate(year, week, weekday string) string {
+	y, _ := strconv.Atoi(year)
+	w, _ := strconv.Atoi(week)
+	d := 1
+	if weekday != "" {
+		d, _ = strconv.Atoi(weekday)
+	}
+	jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.UTC)
+	firstMonday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return firstMonday.AddDate(0, 0, (w-1)*7+d-1).Format("2006-01-02")
+}
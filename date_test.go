@@ -0,0 +1,148 @@
+package easyrss
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string //RFC 3339, with the offset the date should keep
+	}{
+		{"rfc 1123", "Mon, 02 Jan 2006 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"est", "Mon, 02 Jan 2006 15:04:05 EST", "2006-01-02T15:04:05-05:00"},
+		{"edt", "Mon, 02 Jan 2006 15:04:05 EDT", "2006-01-02T15:04:05-04:00"},
+		{"cst", "Mon, 02 Jan 2006 15:04:05 CST", "2006-01-02T15:04:05-06:00"},
+		{"mdt", "Mon, 02 Jan 2006 15:04:05 MDT", "2006-01-02T15:04:05-06:00"},
+		{"pdt", "Mon, 02 Jan 2006 15:04:05 PDT", "2006-01-02T15:04:05-07:00"},
+		{"akst", "Mon, 02 Jan 2006 15:04:05 AKST", "2006-01-02T15:04:05-09:00"},
+		{"hst", "Mon, 02 Jan 2006 15:04:05 hst", "2006-01-02T15:04:05-10:00"},
+		{"ist", "02 Jan 06 15:04 IST", "2006-01-02T15:04:00+05:30"},
+		{"unknown zone", "Wed, 02 Oct 2002 13:00:00 XYZ", "2002-10-02T13:00:00Z"},
+		{"single digit hour", "Mon, 2 Jan 2006 9:04:05 +0000", "2006-01-02T09:04:05Z"},
+		{"missing seconds", "Mon, 02 Jan 2006 15:04 GMT", "2006-01-02T15:04:00Z"},
+		{"single digit hour without seconds", "2 Jan 2006 9:04 EST", "2006-01-02T09:04:00-05:00"},
+		{"trailing GMT+0100", "Mon, 02 Jan 2006 15:04:05 GMT+0100", "2006-01-02T15:04:05+01:00"},
+		{"GMT then offset", "Mon, 02 Jan 2006 15:04:05 GMT +0100", "2006-01-02T15:04:05+01:00"},
+		{"UTC-05:00", "Sat, 07 Sep 2002 00:00:01 UTC-05:00", "2002-09-07T00:00:01-05:00"},
+		{"offset then name", "2006-01-02 15:04:05 -0700 MST", "2006-01-02T15:04:05-07:00"},
+		{"offset comment", "Tue, 10 Jun 2003 04:00:00 +0000 (UTC)", "2003-06-10T04:00:00Z"},
+		{"rfc 850", "Monday, 02-Jan-06 15:04:05 CET", "2006-01-02T15:04:05+01:00"},
+		{"iso week date", "2006-W01-1", "2006-01-02T00:00:00Z"},
+		{"iso week without weekday", "2006-W01", "2006-01-02T00:00:00Z"},
+		{"compact iso week date", "2009W537T10:00Z", "2010-01-03T10:00:00Z"},
+		{"Z without seconds", "2006-01-02T15:04Z", "2006-01-02T15:04:00Z"},
+		{"offset without seconds", "2006-01-02T15:04+02:00", "2006-01-02T15:04:00+02:00"},
+		{"fractional seconds", "2006-01-02T15:04:05.123+01:00", "2006-01-02T15:04:05.123+01:00"},
+		{"decimal comma", "2006-01-02T15:04:05,5Z", "2006-01-02T15:04:05.5Z"},
+		{"compact offset", "2021-09-26T07:30:00.000-0600", "2021-09-26T07:30:00-06:00"},
+		{"slashes", "2006/01/02 15:04:05", "2006-01-02T15:04:05Z"},
+		{"unix date", "Mon Jan  2 15:04:05 MST 2006", "2006-01-02T15:04:05-07:00"},
+		{"ansic", "Mon Jan 2 15:04:05 2006", "2006-01-02T15:04:05Z"},
+		{"long month", "January 2, 2006", "2006-01-02T00:00:00Z"},
+		{"no space after comma", "Mar 14,2023", "2023-03-14T00:00:00Z"},
+		{"surrounding space", "  Sat, 07 Sep 2002 00:00:01 -0400  ", "2002-09-07T00:00:01-04:00"},
+		{"12-hour with zone", "Mon, 2 Jan 2006 3:04:05 PM EST", "2006-01-02T15:04:05-05:00"},
+		{"12-hour", "2 Jan 2006 3:04:05 PM", "2006-01-02T15:04:05Z"},
+		{"12-hour without seconds", "Jan 2, 2006 3:04 pm", "2006-01-02T15:04:00Z"},
+		{"12-hour morning", "2006-01-02 12:30 AM", "2006-01-02T00:30:00Z"},
+		{"12-hour without space", "2 Jan 2006 3:04PM GMT+0100", "2006-01-02T15:04:00+01:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := time.Parse(time.RFC3339Nano, tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseDate(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDate(%q): %v", tt.in, err)
+			}
+			_, gotOffset := got.Zone()
+			_, wantOffset := want.Zone()
+			if !got.Equal(want) || gotOffset != wantOffset {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func TestParseDateErrors(t *testing.T) {
+	for _, in := range []string{"", "not a date", "13:04 PM", "3:04 PM", "3:04PM", "Mar 14 15:04:05", "2006-W54-1", "32 Jan 2006", "Mon, 02 Jan 2006 25:04:05 GMT"} {
+		if got, err := ParseDate(in); !errors.Is(err, ErrBadDate) {
+			t.Errorf("ParseDate(%q) = %v, %v, want ErrBadDate", in, got, err)
+		}
+	}
+}
+
+func TestRegisterDateLayout(t *testing.T) {
+	if _, err := ParseDate("31.12.2020 18h30"); err == nil {
+		t.Fatal("unregistered layout accepted")
+	}
+	RegisterDateLayout("02.01.2006 15h04")
+	if got, err := ParseDate("31.12.2020 18h30"); err != nil || !got.Equal(time.Date(2020, 12, 31, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("registered layout = %v, %v", got, err)
+	}
+}
+
+func TestRegisterTimezone(t *testing.T) {
+	defer RegisterTimezone("IST", 330*time.Minute)
+	RegisterTimezone("ist", time.Hour)
+	if got, err := ParseDate("02 Jan 06 15:04 IST"); err != nil || got.UTC().Hour() != 14 {
+		t.Errorf("overridden IST = %v, %v", got, err)
+	}
+	RegisterTimezone("XST", -3*time.Hour)
+	if got, err := ParseDate("02 Jan 06 15:04 XST"); err != nil || got.UTC().Hour() != 18 {
+		t.Errorf("new zone = %v, %v", got, err)
+	}
+}
+
+//The last layout that matched is tried first, and switching formats still works
+func TestParseDateCache(t *testing.T) {
+	dates := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2006-01-02T15:04:05Z", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"Tue, 03 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 3, 15, 4, 5, 0, time.UTC)},
+		{"Wed, 04 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 4, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-05T15:04:05Z", time.Date(2006, 1, 5, 15, 4, 5, 0, time.UTC)},
+	}
+	for _, d := range dates {
+		got, err := ParseDate(d.in)
+		if err != nil || !got.Equal(d.want) {
+			t.Fatalf("ParseDate(%q) = %v, %v", d.in, got, err)
+		}
+		if layout := dateLayouts[atomic.LoadInt32(&lastDateLayout)]; !got.Equal(mustParse(t, layout, normalizeDate(d.in))) {
+			t.Errorf("cached layout %q doesn't match %q", layout, d.in)
+		}
+	}
+}
+
+func mustParse(t *testing.T, layout, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestNormalizeDate(t *testing.T) {
+	tests := map[string]string{
+		"Mon, 2 Jan 2006 9:04:05 EST":      "2 Jan 2006 09:04:05 -0500",
+		"Mon, 02 Jan 2006 15:04 GMT +0100": "02 Jan 2006 15:04 +0100",
+		"2006-01-02 15:04:05 -0700 MST":    "2006-01-02 15:04:05 -0700",
+		"2 Jan 2006 3:04pm":                "2 Jan 2006 03:04 PM",
+		"2006-W01-1T10:00":                 "2006-01-02T10:00",
+	}
+	for in, want := range tests {
+		if got := normalizeDate(in); got != want {
+			t.Errorf("normalizeDate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
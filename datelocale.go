@@ -0,0 +1,371 @@
+package easyrss
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Month and weekday names of a language, lower case. Localized dates are translated to English with them and then parsed like any
+//other date.
+type dateLocale struct {
+	months   [12][]string //Names and abbreviations of each month, in every grammatical case feeds use
+	weekdays []string
+	fillers  []string //Words dropped before parsing, such as "de" in "14 de marzo de 2023"
+}
+
+var dateLocales = map[string]*dateLocale{
+	"de": {
+		months: [12][]string{{"januar", "jan", "jänner", "jän"}, {"februar", "feb", "feber"}, {"märz", "mär", "mrz", "maerz"},
+			{"april", "apr"}, {"mai"}, {"juni", "jun"}, {"juli", "jul"}, {"august", "aug"}, {"september", "sep", "sept"},
+			{"oktober", "okt"}, {"november", "nov"}, {"dezember", "dez"}},
+		weekdays: []string{"montag", "mo", "dienstag", "di", "mittwoch", "mi", "donnerstag", "do", "freitag", "fr", "samstag", "sonnabend", "sa", "sonntag", "so"},
+		fillers:  []string{"den", "um", "uhr"},
+	},
+	"fr": {
+		months: [12][]string{{"janvier", "janv", "jan"}, {"février", "févr", "fév", "fevrier", "fevr", "fev"}, {"mars", "mar"},
+			{"avril", "avr"}, {"mai"}, {"juin"}, {"juillet", "juil"}, {"août", "aout", "aoû"}, {"septembre", "sept", "sep"},
+			{"octobre", "oct"}, {"novembre", "nov"}, {"décembre", "déc", "decembre", "dec"}},
+		weekdays: []string{"lundi", "lun", "mardi", "mar", "mercredi", "mer", "jeudi", "jeu", "vendredi", "ven", "samedi", "sam", "dimanche", "dim"},
+		fillers:  []string{"le", "à", "a"},
+	},
+	"es": {
+		months: [12][]string{{"enero", "ene"}, {"febrero", "feb"}, {"marzo", "mar"}, {"abril", "abr"}, {"mayo", "may"},
+			{"junio", "jun"}, {"julio", "jul"}, {"agosto", "ago"}, {"septiembre", "setiembre", "sept", "sep", "set"},
+			{"octubre", "oct"}, {"noviembre", "nov"}, {"diciembre", "dic"}},
+		weekdays: []string{"lunes", "lun", "martes", "mar", "miércoles", "miercoles", "mié", "mie", "jueves", "jue", "viernes", "vie", "sábado", "sabado", "sáb", "sab", "domingo", "dom"},
+		fillers:  []string{"de", "del", "a", "las", "la"},
+	},
+	"it": {
+		months: [12][]string{{"gennaio", "gen"}, {"febbraio", "feb"}, {"marzo", "mar"}, {"aprile", "apr"}, {"maggio", "mag"},
+			{"giugno", "giu"}, {"luglio", "lug"}, {"agosto", "ago"}, {"settembre", "set"}, {"ottobre", "ott"},
+			{"novembre", "nov"}, {"dicembre", "dic"}},
+		weekdays: []string{"lunedì", "lunedi", "lun", "martedì", "martedi", "mar", "mercoledì", "mercoledi", "mer", "giovedì", "giovedi", "gio", "venerdì", "venerdi", "ven", "sabato", "sab", "domenica", "dom"},
+		fillers:  []string{"il", "alle", "ore"},
+	},
+	"pt": {
+		months: [12][]string{{"janeiro", "jan"}, {"fevereiro", "fev"}, {"março", "marco", "mar"}, {"abril", "abr"}, {"maio", "mai"},
+			{"junho", "jun"}, {"julho", "jul"}, {"agosto", "ago"}, {"setembro", "set"}, {"outubro", "out"},
+			{"novembro", "nov"}, {"dezembro", "dez"}},
+		weekdays: []string{"segunda-feira", "segunda", "seg", "terça-feira", "terça", "terca", "ter", "quarta-feira", "quarta", "qua", "quinta-feira", "quinta", "qui", "sexta-feira", "sexta", "sex", "sábado", "sabado", "sáb", "sab", "domingo", "dom"},
+		fillers:  []string{"de", "às", "as"},
+	},
+	"nl": {
+		months: [12][]string{{"januari", "jan"}, {"februari", "feb"}, {"maart", "mrt", "maa"}, {"april", "apr"}, {"mei"},
+			{"juni", "jun"}, {"juli", "jul"}, {"augustus", "aug"}, {"september", "sep", "sept"}, {"oktober", "okt"},
+			{"november", "nov"}, {"december", "dec"}},
+		weekdays: []string{"maandag", "ma", "dinsdag", "di", "woensdag", "wo", "donderdag", "do", "vrijdag", "vr", "zaterdag", "za", "zondag", "zo"},
+		fillers:  []string{"om", "uur"},
+	},
+	"sv": {
+		months: [12][]string{{"januari", "jan"}, {"februari", "feb"}, {"mars", "mar"}, {"april", "apr"}, {"maj"}, {"juni", "jun"},
+			{"juli", "jul"}, {"augusti", "aug"}, {"september", "sep", "sept"}, {"oktober", "okt"}, {"november", "nov"},
+			{"december", "dec"}},
+		weekdays: []string{"måndag", "mån", "tisdag", "tis", "onsdag", "ons", "torsdag", "tors", "tor", "fredag", "fre", "lördag", "lör", "söndag", "sön"},
+		fillers:  []string{"den", "kl", "klockan"},
+	},
+	"da": {
+		months: [12][]string{{"januar", "jan"}, {"februar", "feb"}, {"marts", "mar"}, {"april", "apr"}, {"maj"}, {"juni", "jun"},
+			{"juli", "jul"}, {"august", "aug"}, {"september", "sep", "sept"}, {"oktober", "okt"}, {"november", "nov"},
+			{"december", "dec"}},
+		weekdays: []string{"mandag", "man", "tirsdag", "tir", "onsdag", "ons", "torsdag", "tor", "fredag", "fre", "lørdag", "lør", "søndag", "søn"},
+		fillers:  []string{"den", "kl", "klokken"},
+	},
+	"no": {
+		months: [12][]string{{"januar", "jan"}, {"februar", "feb"}, {"mars", "mar"}, {"april", "apr"}, {"mai"}, {"juni", "jun"},
+			{"juli", "jul"}, {"august", "aug"}, {"september", "sep", "sept"}, {"oktober", "okt"}, {"november", "nov"},
+			{"desember", "des"}},
+		weekdays: []string{"mandag", "man", "tirsdag", "tir", "onsdag", "ons", "torsdag", "tor", "fredag", "fre", "lørdag", "lør", "søndag", "søn"},
+		fillers:  []string{"den", "kl", "klokken"},
+	},
+	"fi": {
+		months: [12][]string{{"tammikuu", "tammikuuta", "tammi"}, {"helmikuu", "helmikuuta", "helmi"}, {"maaliskuu", "maaliskuuta", "maalis"},
+			{"huhtikuu", "huhtikuuta", "huhti"}, {"toukokuu", "toukokuuta", "touko"}, {"kesäkuu", "kesäkuuta", "kesä"},
+			{"heinäkuu", "heinäkuuta", "heinä"}, {"elokuu", "elokuuta", "elo"}, {"syyskuu", "syyskuuta", "syys"},
+			{"lokakuu", "lokakuuta", "loka"}, {"marraskuu", "marraskuuta", "marras"}, {"joulukuu", "joulukuuta", "joulu"}},
+		weekdays: []string{"maanantai", "ma", "tiistai", "ti", "keskiviikko", "ke", "torstai", "to", "perjantai", "pe", "lauantai", "la", "sunnuntai", "su"},
+		fillers:  []string{"klo", "kello"},
+	},
+	"pl": {
+		months: [12][]string{{"styczeń", "stycznia", "sty"}, {"luty", "lutego", "lut"}, {"marzec", "marca", "mar"},
+			{"kwiecień", "kwietnia", "kwi"}, {"maj", "maja"}, {"czerwiec", "czerwca", "cze"}, {"lipiec", "lipca", "lip"},
+			{"sierpień", "sierpnia", "sie"}, {"wrzesień", "września", "wrz"}, {"październik", "października", "paź", "paz"},
+			{"listopad", "listopada", "lis"}, {"grudzień", "grudnia", "gru"}},
+		weekdays: []string{"poniedziałek", "pon", "wtorek", "wt", "środa", "śr", "czwartek", "czw", "piątek", "pt", "sobota", "sob", "niedziela", "niedz", "nd"},
+		fillers:  []string{"r", "roku", "o", "godz"},
+	},
+	"cs": {
+		months: [12][]string{{"leden", "ledna", "led"}, {"únor", "února", "úno"}, {"březen", "března", "bře"}, {"duben", "dubna", "dub"},
+			{"květen", "května", "kvě"}, {"červen", "června", "čer"}, {"červenec", "července", "čvc"}, {"srpen", "srpna", "srp"},
+			{"září", "zář"}, {"říjen", "října", "říj"}, {"listopad", "listopadu", "lis"}, {"prosinec", "prosince", "pro"}},
+		weekdays: []string{"pondělí", "po", "úterý", "út", "středa", "st", "čtvrtek", "čt", "pátek", "pá", "sobota", "so", "neděle", "ne"},
+		fillers:  []string{"v"},
+	},
+	"ru": {
+		months: [12][]string{{"январь", "января", "янв"}, {"февраль", "февраля", "фев"}, {"март", "марта", "мар"},
+			{"апрель", "апреля", "апр"}, {"май", "мая"}, {"июнь", "июня", "июн"}, {"июль", "июля", "июл"},
+			{"август", "августа", "авг"}, {"сентябрь", "сентября", "сен", "сент"}, {"октябрь", "октября", "окт"},
+			{"ноябрь", "ноября", "ноя"}, {"декабрь", "декабря", "дек"}},
+		weekdays: []string{"понедельник", "пн", "вторник", "вт", "среда", "ср", "четверг", "чт", "пятница", "пт", "суббота", "сб", "воскресенье", "вс"},
+		fillers:  []string{"г", "год", "года", "в"},
+	},
+	"uk": {
+		months: [12][]string{{"січень", "січня", "січ"}, {"лютий", "лютого", "лют"}, {"березень", "березня", "бер"},
+			{"квітень", "квітня", "кві"}, {"травень", "травня", "тра"}, {"червень", "червня", "чер"}, {"липень", "липня", "лип"},
+			{"серпень", "серпня", "сер"}, {"вересень", "вересня", "вер"}, {"жовтень", "жовтня", "жов"},
+			{"листопад", "листопада", "лис"}, {"грудень", "грудня", "гру"}},
+		weekdays: []string{"понеділок", "пн", "вівторок", "вт", "середа", "ср", "четвер", "чт", "пʼятниця", "п'ятниця", "пт", "субота", "сб", "неділя", "нд"},
+		fillers:  []string{"р", "року", "о"},
+	},
+	"tr": {
+		months: [12][]string{{"ocak", "oca"}, {"şubat", "subat", "şub", "sub"}, {"mart", "mar"}, {"nisan", "nis"}, {"mayıs", "mayis", "may"},
+			{"haziran", "haz"}, {"temmuz", "tem"}, {"ağustos", "agustos", "ağu", "agu"}, {"eylül", "eylul", "eyl"},
+			{"ekim", "eki"}, {"kasım", "kasim", "kas"}, {"aralık", "aralik", "ara"}},
+		weekdays: []string{"pazartesi", "pzt", "salı", "sali", "sal", "çarşamba", "carsamba", "çar", "perşembe", "persembe", "per", "cuma", "cum", "cumartesi", "cmt", "pazar", "paz"},
+	},
+	"hu": {
+		months: [12][]string{{"január", "jan"}, {"február", "febr", "feb"}, {"március", "márc", "mar"}, {"április", "ápr"},
+			{"május", "máj"}, {"június", "jún"}, {"július", "júl"}, {"augusztus", "aug"}, {"szeptember", "szept"},
+			{"október", "okt"}, {"november", "nov"}, {"december", "dec"}},
+		weekdays: []string{"hétfő", "kedd", "szerda", "csütörtök", "péntek", "szombat", "vasárnap"},
+	},
+}
+
+//Order locales are tried in when the feed language doesn't help
+var dateLocaleOrder = []string{"de", "fr", "es", "it", "pt", "nl", "sv", "da", "no", "fi", "pl", "cs", "ru", "uk", "tr", "hu"}
+
+var clockHours = regexp.MustCompile(`^(\d{1,2})h(\d{2})?$`)  //French style "15h04"
+var dottedClock = regexp.MustCompile(`^(\d{1,2})\.(\d{2})$`) //Finnish style "15.04"
+var ordinalDay = regexp.MustCompile(`^(\d{1,2})(?:er|º|ª)$`)
+var cjkDate = regexp.MustCompile(`(\d{2,4})\s*[年년]\s*(\d{1,2})\s*[月월]\s*(\d{1,2})\s*[日일号號]?`)
+var cjkTime = regexp.MustCompile(`(\d{1,2})\s*[時时시:]\s*(\d{1,2})(?:\s*[分분:]\s*(\d{1,2}))?`)
+
+const cjkNumerals = "〇零一二三四五六七八九十"
+
+//Same as ParseDate, but also understands month and weekday names in the major European languages, e.g. "mardi 14 mars 2023" or
+//"14 марта 2023", and Chinese, Japanese and Korean dates such as "2023年3月14日", including ones written in CJK numerals.
+//Language, an RFC 5646 tag such as "fr-CA", picks the locale tried first. Every other known locale is tried after it.
+func ParseDateIn(value, language string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrBadDate
+	}
+	if parsed, ok := parseStandardDate(value); ok {
+		return parsed, nil
+	}
+	if parsed, ok := parseLocalizedDate(value, language); ok {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrBadDate, value)
+}
+
+func parseLocalizedDate(value, language string) (time.Time, bool) {
+	if strings.ContainsAny(value, "年년") {
+		return parseCJKDate(value)
+	}
+	language = strings.ToLower(language)
+	if dash := strings.IndexAny(language, "-_"); dash >= 0 {
+		language = language[:dash]
+	}
+	if language == "nb" || language == "nn" {
+		language = "no"
+	}
+	if locale, ok := dateLocales[language]; ok {
+		if parsed, ok := locale.parse(value); ok {
+			return parsed, true
+		}
+	}
+	for _, code := range dateLocaleOrder {
+		if code == language {
+			continue
+		}
+		if parsed, ok := dateLocales[code].parse(value); ok {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+//Translates value to English and parses it. Fails if no month name of the locale is found.
+func (l *dateLocale) parse(value string) (time.Time, bool) {
+	tokens := strings.Fields(strings.Replace(decimalComma(value), ",", " ", -1))
+	translated := make([]string, 0, len(tokens))
+	foundMonth := false
+	for t, token := range tokens {
+		token = strings.TrimRight(token, ".")
+		word := strings.ToLower(token)
+		isWeekday := containsWord(l.weekdays, word)
+		if month := l.month(word); month != 0 && !(isWeekday && t == 0) { //"mar." opens French and Spanish dates as a weekday
+			translated = append(translated, month.String()[:3])
+			foundMonth = true
+			continue
+		}
+		if isWeekday || containsWord(l.fillers, word) {
+			continue
+		}
+		if hours := clockHours.FindStringSubmatch(word); hours != nil {
+			token = hours[1] + ":" + hours[2]
+			if hours[2] == "" {
+				token += "00"
+			}
+		} else if clock := dottedClock.FindStringSubmatch(word); clock != nil && foundMonth { //Before the month it would be a day and month
+			token = clock[1] + ":" + clock[2]
+		} else if day := ordinalDay.FindStringSubmatch(word); day != nil {
+			token = day[1]
+		}
+		translated = append(translated, token)
+	}
+	if !foundMonth {
+		return time.Time{}, false
+	}
+	return parseStandardDate(strings.Join(translated, " "))
+}
+
+func (l *dateLocale) month(word string) time.Month {
+	for m, names := range l.months {
+		if containsWord(names, word) {
+			return time.Month(m + 1)
+		}
+	}
+	return 0
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+//Parses dates such as "2023年3月14日 15時04分", "2023年03月14日(火) 午後3:04 JST" or "2023년 3월 14일 오후 3:04"
+func parseCJKDate(value string) (time.Time, bool) {
+	value = cjkToASCII(value)
+	date := cjkDate.FindStringSubmatchIndex(value)
+	if date == nil {
+		return time.Time{}, false
+	}
+	year, _ := strconv.Atoi(value[date[2]:date[3]])
+	month, _ := strconv.Atoi(value[date[4]:date[5]])
+	day, _ := strconv.Atoi(value[date[6]:date[7]])
+	if year < 100 {
+		year += 2000
+	}
+	normalized := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	rest := value[date[1]:]
+	if clock := cjkTime.FindStringSubmatchIndex(rest); clock != nil {
+		hour, _ := strconv.Atoi(rest[clock[2]:clock[3]])
+		minute, _ := strconv.Atoi(rest[clock[4]:clock[5]])
+		second := 0
+		if clock[6] >= 0 {
+			second, _ = strconv.Atoi(rest[clock[6]:clock[7]])
+		}
+		if hour < 12 && (strings.Contains(value, "午後") || strings.Contains(value, "下午") || strings.Contains(value, "오후")) {
+			hour += 12
+		}
+		normalized += fmt.Sprintf(" %02d:%02d:%02d %s", hour, minute, second, strings.TrimLeft(rest[clock[1]:], "分분秒초 "))
+	}
+	return parseStandardDate(strings.TrimSpace(normalized))
+}
+
+//Replaces full-width digits and colons and runs of CJK numerals with ASCII
+func cjkToASCII(value string) string {
+	var b strings.Builder
+	runes := []rune(value)
+	for r := 0; r < len(runes); {
+		switch {
+		case runes[r] >= '０' && runes[r] <= '９':
+			b.WriteRune('0' + runes[r] - '０')
+			r++
+			continue
+		case runes[r] == '：':
+			b.WriteRune(':')
+			r++
+			continue
+		}
+		end := r
+		for end < len(runes) && strings.ContainsRune(cjkNumerals, runes[end]) {
+			end++
+		}
+		if end == r {
+			b.WriteRune(runes[r])
+			r++
+			continue
+		}
+		b.WriteString(strconv.Itoa(cjkNumber(runes[r:end])))
+		r = end
+	}
+	return b.String()
+}
+
+//Value of a run of CJK numerals, either positional ("二〇二三") or with tens ("十四", "三十一")
+func cjkNumber(numerals []rune) int {
+	positional := func(digits []rune) int {
+		n := 0
+		for _, digit := range digits {
+			value := 0
+			switch digit {
+			case '〇', '零':
+				value = 0
+			default:
+				value = strings.IndexRune("一二三四五六七八九", digit)/len("一") + 1
+			}
+			n = n*10 + value
+		}
+		return n
+	}
+	for t, numeral := range numerals {
+		if numeral == '十' {
+			tens := 1
+			if t > 0 {
+				tens = positional(numerals[:t])
+			}
+			return tens*10 + positional(numerals[t+1:])
+		}
+	}
+	return positional(numerals)
+}
+
+//Sets the item date from the feed. Dates that aren't in a standard format are parsed with every locale and kept, so resolveDate can
+//parse them again once the channel language is known.
+func (i *Item) setDate(text string) {
+	setLocalizedDate(&i.date, &i.dateText, text)
+}
+
+//Parses a localized item date again with language as a hint
+func (i *Item) resolveDate(language string) {
+	resolveLocalizedDate(&i.date, &i.dateText, language)
+}
+
+//Parses localized channel dates again with language as a hint, as items do
+func (c *Channel) resolveDates(language string) {
+	resolveLocalizedDate(&c.pubDate, &c.pubDateText, language)
+	resolveLocalizedDate(&c.lastBuild, &c.lastBuildText, language)
+}
+
+//Sets date from text. If text isn't in a standard format it is parsed with every locale and kept in dateText until
+//resolveLocalizedDate knows the language.
+func setLocalizedDate(date **time.Time, dateText *string, text string) {
+	text = strings.TrimSpace(text)
+	if parsed, ok := parseStandardDate(text); ok {
+		*date, *dateText = &parsed, ""
+		return
+	}
+	*date, *dateText = nil, text
+	if parsed, ok := parseLocalizedDate(text, ""); ok {
+		*date = &parsed
+	}
+}
+
+func resolveLocalizedDate(date **time.Time, dateText *string, language string) {
+	if *dateText == "" {
+		return
+	}
+	if parsed, ok := parseLocalizedDate(*dateText, language); ok {
+		*date = &parsed
+	}
+	*dateText = ""
+}
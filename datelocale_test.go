@@ -0,0 +1,112 @@
+package easyrss
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateIn(t *testing.T) {
+	tests := []struct {
+		in, language, want string
+	}{
+		{"Di, 14 Mär 2023 15:04:05 +0100", "de", "2023-03-14T15:04:05+01:00"},
+		{"Dienstag, 14. März 2023 um 15:04 Uhr", "de-DE", "2023-03-14T15:04:00Z"},
+		{"mardi 14 mars 2023", "fr", "2023-03-14T00:00:00Z"},
+		{"mar. 14 mars 2023 15h04", "fr", "2023-03-14T15:04:00Z"},
+		{"1er janvier 2023", "fr", "2023-01-01T00:00:00Z"},
+		{"martes, 14 de marzo de 2023 15:04:05 CET", "es", "2023-03-14T15:04:05+01:00"},
+		{"martedì 14 marzo 2023 alle 15:04", "it", "2023-03-14T15:04:00Z"},
+		{"terça-feira, 14 de março de 2023", "pt-BR", "2023-03-14T00:00:00Z"},
+		{"14 марта 2023 г. в 15:04", "ru", "2023-03-14T15:04:00Z"},
+		{"14 березня 2023", "uk", "2023-03-14T00:00:00Z"},
+		{"wtorek, 14 marca 2023", "pl", "2023-03-14T00:00:00Z"},
+		{"14. listopadu 2023", "cs", "2023-11-14T00:00:00Z"},
+		{"2023. március 14., kedd", "hu", "2023-03-14T00:00:00Z"},
+		{"14 mei 2023 om 15:04", "", "2023-05-14T15:04:00Z"},
+		{"tiistai 14. maaliskuuta 2023 klo 15.04", "fi", "2023-03-14T15:04:00Z"},
+		{"2023年3月14日", "zh", "2023-03-14T00:00:00Z"},
+		{"2023年03月14日(火) 午後3:04 JST", "ja", "2023-03-14T15:04:00+09:00"},
+		{"2023년 3월 14일 오후 3:04", "ko", "2023-03-14T15:04:00Z"},
+		{"二〇二三年三月十四日 15時04分05秒", "", "2023-03-14T15:04:05Z"},
+		{"２０２３年１２月３１日", "", "2023-12-31T00:00:00Z"},
+		{"14.03.2023 15:04", "", "2023-03-14T15:04:00Z"},
+		{"14.03.2023 15.04", "", "2023-03-14T15:04:00Z"},
+		{"14.3.2023 9.05 +0200", "", "2023-03-14T09:05:00+02:00"},
+		{"14.03.2023, 15.04 Uhr", "de", "2023-03-14T15:04:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			want, err := time.Parse(time.RFC3339, tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseDateIn(tt.in, tt.language)
+			if err != nil || !got.Equal(want) {
+				t.Errorf("ParseDateIn(%q, %q) = %v, %v, want %v", tt.in, tt.language, got, err, want)
+			}
+		})
+	}
+	if _, err := ParseDateIn("14 Brumaire 2023", "fr"); !errors.Is(err, ErrBadDate) {
+		t.Errorf("unknown month error = %v, want ErrBadDate", err)
+	}
+}
+
+//Channel dates are localized like item dates, and resolved with the channel language even when it comes last
+const localizedChannelFeed = `<rss version="2.0"><channel><title>T</title><link>http://t</link><description>d</description>
+<pubDate>mardi 14 mars 2023 10:00:00 +0100</pubDate><lastBuildDate>mercredi 15 mars 2023 11:30:00 +0100</lastBuildDate>
+<item><title>a</title><pubDate>ter, 14 mar 2023 10:00:00 +0000</pubDate></item>
+<language>fr-FR</language></channel></rss>`
+
+func TestLocalizedFeedDates(t *testing.T) {
+	full, err := Decode([]byte(localizedChannelFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, r := range map[string]*RSS{"decode": full, "stream": streamFeed(t, localizedChannelFeed)} {
+		if d, err := r.PubDate(); err != nil || !d.Equal(time.Date(2023, 3, 14, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s pubDate = %v, %v", name, d, err)
+		}
+		if d, err := r.LastBuildDate(); err != nil || !d.Equal(time.Date(2023, 3, 15, 10, 30, 0, 0, time.UTC)) {
+			t.Errorf("%s lastBuildDate = %v, %v", name, d, err)
+		}
+		if r.channel.pubDateText != "" || r.channel.lastBuildText != "" {
+			t.Errorf("%s channel dates left unresolved: %q, %q", name, r.channel.pubDateText, r.channel.lastBuildText)
+		}
+		items, _ := r.Items()
+		if d, err := items[0].Date(); err != nil || !d.Equal(time.Date(2023, 3, 14, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s item date = %v, %v", name, d, err)
+		}
+	}
+}
+
+func TestLocalizedRDFDate(t *testing.T) {
+	const doc = `<?xml version="1.0"?><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel rdf:about="http://x"><title>T</title><link>http://x</link><description>D</description><dc:date>14. März 2023</dc:date><dc:language>de</dc:language></channel>
+<item rdf:about="http://x/1"><title>a</title><link>http://x/1</link></item></rdf:RDF>`
+	r, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := r.PubDate(); err != nil || !d.Equal(time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("dc:date = %v, %v", d, err)
+	}
+}
+
+//A pubDate only takes precedence over dc:date when it could be read
+func TestUnreadableDateBeforeDublinCore(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>T</title><link>http://x</link><description>D</description>
+<pubDate>sometime</pubDate><dc:date>2023-03-14</dc:date><item><title>a</title><pubDate>sometime</pubDate><dc:date>2023-03-14</dc:date></item></channel></rss>`
+	r, err := Decode([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	if d, err := r.PubDate(); err != nil || !d.Equal(want) {
+		t.Errorf("channel date = %v, %v, want %v", d, err, want)
+	}
+	items, _ := r.Items()
+	if d, err := items[0].Date(); err != nil || !d.Equal(want) {
+		t.Errorf("item date = %v, %v, want %v", d, err, want)
+	}
+}
@@ -0,0 +1,132 @@
+package easyrss
+
+import (
+	"bufio"
+	"io"
+)
+
+//Decodes a feed incrementally from a reader. Channel metadata is parsed up front by NewDecoder, then items are returned one at a time
+//by Next. Each item's subtree is discarded once decoded, so memory use stays bounded no matter how many items the feed contains.
+//Streaming always uses the pure-Go parser, even when built with the libxml2 tag. JSON Feed documents can't be streamed and are
+//decoded in full. Podcasting 2.0 live items aren't streamed either: they are few, so they are kept with the channel metadata.
+type Decoder struct {
+	builder *treeBuilder
+	feed    RSS    //Channel metadata, items are never stored here
+	queue   []Item //Decoded items waiting to be returned by Next
+	done    bool
+}
+
+//Reads up to and including the first item of the feed in r and returns a Decoder positioned at that item.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	if isJSON(head) {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		feed, err := DecodeJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		d := &Decoder{feed: *feed, queue: feed.channel.items, done: true}
+		d.feed.channel.items = nil
+		return d, nil
+	}
+	d := &Decoder{builder: newTreeBuilder(br)}
+	for len(d.queue) == 0 && !d.done {
+		if err := d.step(); err != nil {
+			return nil, err
+		}
+	}
+	root := d.builder.root
+	if root == nil {
+		return nil, ErrNotFeed
+	}
+	if !d.feed.channel.isAtom && !d.feed.channel.isRDF && root.name != "rss" {
+		return nil, ErrNotFeed
+	}
+	return d, nil
+}
+
+//Returns the channel metadata read so far. The returned feed never contains items, use Next for those. Metadata elements placed
+//after the first item only show up once Next has read past them. Live items are all there, with their dates resolved against the
+//channel language, once Next has returned io.EOF.
+func (d *Decoder) Channel() *RSS {
+	return &d.feed
+}
+
+//Returns the next item in the feed, or io.EOF once every item has been read.
+func (d *Decoder) Next() (*Item, error) {
+	for len(d.queue) == 0 {
+		if d.done {
+			return nil, io.EOF
+		}
+		if err := d.step(); err != nil {
+			return nil, err
+		}
+	}
+	item := d.queue[0]
+	d.queue = d.queue[1:]
+	item.resolveDate(d.feed.channel.language) //The language usually precedes the items, but only needs to precede this one
+	item.inheritAuthor(d.feed.channel.atomAuthor)
+	return &item, nil
+}
+
+//Reads until the next element closes and folds it into the channel metadata or the item queue
+func (d *Decoder) step() error {
+	n, err := d.builder.next()
+	if err == io.EOF {
+		d.done = true
+		d.feed.channel.resolveDates(d.feed.channel.language)
+		for liveID := range d.feed.channel.liveItems {
+			d.feed.channel.liveItems[liveID].resolveDate(d.feed.channel.language)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	root := d.builder.root
+	parent := n.parent
+	if parent == nil {
+		return nil //The document element itself
+	}
+	switch {
+	case root.name == "feed" && root.namespace == atomNS:
+		if !d.feed.channel.isAtom {
+			d.feed.channel.isAtom = true
+			d.feed.channel.language = root.Attr("lang")
+		}
+		if parent != root {
+			return nil
+		}
+		if n.name == "entry" && n.namespace == atomNS {
+			item := Item{}
+			getAtomEntry(&item, n)
+			d.queue = append(d.queue, item)
+		} else {
+			setAtomFeedField(&d.feed, n)
+		}
+	default:
+		isRDF := root.name == "RDF" && root.namespace == rdfNS
+		d.feed.channel.isRDF = isRDF
+		inChannel := parent.name == "channel" && parent.parent == root
+		switch {
+		case n.name == "item" && (inChannel || isRDF && parent == root):
+			item := Item{}
+			getItemMeta(&item, n)
+			d.queue = append(d.queue, item)
+		case inChannel, isRDF && parent == root && (n.name == "image" || n.name == "textinput"):
+			setChannelField(&d.feed, n)
+		default:
+			return nil //Nested inside an element we haven't finished reading yet
+		}
+	}
+	//Drop the decoded subtree along with the whitespace before it so nothing accumulates between items
+	if prev := n.prevSibling; prev != nil && prev.isText {
+		prev.detach()
+	}
+	n.detach()
+	return nil
+}
@@ -0,0 +1,166 @@
+package easyrss
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const decoderLiveFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>Live</title><link>http://x</link>
+<description>D</description>
+<podcast:liveItem status="pending" start="2024-05-01T18:00:00Z"><title>Show</title><pubDate>Mi, 1 Mai 2024 18:00:00 +0200</pubDate></podcast:liveItem>
+<item><title>Episode</title><pubDate>Di, 30 Apr 2024 10:00:00 +0200</pubDate></item>
+<language>de</language>
+</channel></rss>`
+
+const decoderGermanFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><link>http://x</link><description>D</description>
+<language>de</language><item><title>a</title><pubDate>Di, 14 Mär 2023 10:00:00 +0100</pubDate></item>
+<item><title>b</title><pubDate>Mi, 15 Mär 2023 10:00:00 +0100</pubDate></item></channel></rss>`
+
+//Reads a whole feed through a Decoder
+func streamFeed(t *testing.T, doc string) *RSS {
+	t.Helper()
+	d, err := NewDecoder(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []Item
+	for {
+		item, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, *item)
+	}
+	streamed := *d.Channel()
+	streamed.channel.items = items
+	return &streamed
+}
+
+//Runs check on a feed read by Decode, by a Decoder, and decoded again after Encode
+func checkAllPaths(t *testing.T, feed string, check func(*testing.T, *RSS)) {
+	t.Helper()
+	r, err := Decode([]byte(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("decode", func(t *testing.T) { check(t, r) })
+	t.Run("stream", func(t *testing.T) { check(t, streamFeed(t, feed)) })
+	t.Run("round trip", func(t *testing.T) {
+		out, err := Encode(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := Decode(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, decoded)
+	})
+}
+
+//A streamed feed must decode to exactly what Decode returns
+func TestDecoderMatchesDecode(t *testing.T) {
+	tests := map[string]string{
+		"rss":       encodePlainFeed,
+		"itunes":    encodeItunesFeed,
+		"mrss":      encodeMRSSFeed,
+		"atom":      atomFeed,
+		"rdf":       rdfFeed,
+		"json":      jsonFeedDoc,
+		"localized": decoderGermanFeed,
+		"live":      decoderLiveFeed,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			full, err := Decode([]byte(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if streamed := streamFeed(t, doc); !reflect.DeepEqual(streamed, full) {
+				t.Errorf("streamed feed differs\nstreamed: %+v\ndecoded:  %+v", streamed.channel, full.channel)
+			}
+		})
+	}
+}
+
+//Live items come before the channel language, so their localized dates can only be resolved once the channel is finished
+func TestDecoderLiveItemDates(t *testing.T) {
+	r := streamFeed(t, decoderLiveFeed)
+	live, err := r.LiveItems()
+	if err != nil || len(live) != 1 {
+		t.Fatal(live, err)
+	}
+	if d, err := live[0].Date(); err != nil || !d.Equal(time.Date(2024, 5, 1, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("live item date = %v, %v", d, err)
+	}
+}
+
+func TestDecoderNotFeed(t *testing.T) {
+	for _, doc := range []string{"<html><body/></html>", "", `{"version": "nope"}`} {
+		if _, err := NewDecoder(strings.NewReader(doc)); err == nil {
+			t.Errorf("NewDecoder(%q) succeeded", doc)
+		}
+	}
+}
+
+//Generates a feed with n items without holding it in memory
+type itemGenerator struct {
+	n, i int
+	buf  []byte
+}
+
+func (g *itemGenerator) Read(p []byte) (int, error) {
+	for len(g.buf) == 0 {
+		switch {
+		case g.i == 0:
+			g.buf = []byte(`<rss version="2.0"><channel><title>Big</title>`)
+		case g.i <= g.n:
+			g.buf = []byte("\n<item><title>item</title><description>" + strings.Repeat("x", 1000) + "</description></item>")
+		case g.i == g.n+1:
+			g.buf = []byte("\n</channel></rss>")
+		default:
+			return 0, io.EOF
+		}
+		g.i++
+	}
+	copied := copy(p, g.buf)
+	g.buf = g.buf[copied:]
+	return copied, nil
+}
+
+//Decoded items must not stay attached to the tree
+func TestDecoderBoundedTree(t *testing.T) {
+	d, err := NewDecoder(&itemGenerator{n: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for {
+		if _, err := d.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		count++
+		if count%1000 == 0 {
+			channel := d.builder.root.firstChild
+			children := 0
+			for child := channel.firstChild; child != nil; child = child.nextSibling {
+				children++
+			}
+			if children > 4 {
+				t.Fatalf("%d nodes left under the channel after %d items", children, count)
+			}
+		}
+	}
+	if count != 10000 {
+		t.Errorf("%d items, want 10000", count)
+	}
+}
@@ -0,0 +1,217 @@
+package easyrss
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+//MIME types advertised by <link rel="alternate"> feed links, and how much each is preferred
+var feedLinkTypes = map[string]int{
+	"application/rss+xml":   30,
+	"application/atom+xml":  30,
+	"application/feed+json": 20,
+	"application/json":      10,
+	"application/rdf+xml":   20,
+	"application/xml":       5,
+	"text/xml":              5,
+}
+
+//Paths probed when a page doesn't advertise its feeds
+var feedProbePaths = []string{"/feed", "/rss.xml", "/atom.xml", "/feed.xml", "/index.xml", "/rss", "/feed.json"}
+
+//How a candidate was found
+const (
+	CandidateLink  = "link"  //Advertised by a <link rel="alternate"> element
+	CandidateProbe = "probe" //Found by probing a common feed path
+	CandidateSelf  = "self"  //The page URL itself is a feed
+)
+
+//A possible feed for a web page. Candidates are returned best first.
+type FeedCandidate struct {
+	URL      string
+	Title    string //From the link title attribute, or the feed title once verified
+	Type     string //Advertised MIME type, if any
+	Source   string //One of CandidateLink, CandidateProbe or CandidateSelf
+	Score    int    //Higher is better
+	Verified bool   //Whether the candidate was fetched and decoded successfully
+	Feed     *RSS   //The decoded feed when Verified is set
+}
+
+//Finds feeds for web pages over HTTP. The zero value uses http.DefaultClient, neither probes nor verifies.
+type Discoverer struct {
+	Client  *http.Client //Client used for requests. If nil, http.DefaultClient is used.
+	Verify  bool         //Fetch and Decode every candidate, dropping the ones that fail
+	Probe   bool         //Also try common feed paths on the page's host. Probed paths are always verified.
+	MaxSize int64        //Largest page or feed read, in bytes. If zero, DefaultMaxFeedSize is used.
+}
+
+//Extracts feed candidates from the <link rel="alternate"> elements of an HTML document. Relative links are resolved against base,
+//or against the document's own <base href> when present. base may be nil if the document only uses absolute links.
+func DiscoverHTML(html []byte, base *url.URL) []FeedCandidate {
+	var candidates []FeedCandidate
+	seen := make(map[string]bool)
+	tok := newXMLTokenizer(bytes.NewReader(html))
+	for {
+		t, err := tok.next()
+		if err != nil {
+			break
+		}
+		if t.kind != startToken || t.prefix != "" {
+			continue
+		}
+		attrs := make(map[string]string, len(t.attrs))
+		for _, attr := range t.attrs {
+			attrs[strings.ToLower(attr.name)] = strings.TrimSpace(attr.value)
+		}
+		switch strings.ToLower(t.name) {
+		case "base":
+			if href, err := url.Parse(attrs["href"]); err == nil && attrs["href"] != "" {
+				if base != nil {
+					href = base.ResolveReference(href)
+				}
+				base = href
+			}
+		case "link":
+			mediaType := strings.ToLower(attrs["type"])
+			score, isFeed := feedLinkTypes[mediaType]
+			if !isFeed || !hasToken(attrs["rel"], "alternate") || attrs["href"] == "" {
+				continue
+			}
+			href, err := url.Parse(attrs["href"])
+			if err != nil {
+				continue
+			}
+			if base != nil {
+				href = base.ResolveReference(href)
+			}
+			title := attrs["title"]
+			if strings.Contains(strings.ToLower(title), "comment") { //Comment feeds are rarely what people subscribe to
+				score -= 15
+			}
+			if seen[href.String()] {
+				continue
+			}
+			seen[href.String()] = true
+			candidates = append(candidates, FeedCandidate{
+				URL:    href.String(),
+				Title:  title,
+				Type:   mediaType,
+				Source: CandidateLink,
+				Score:  score + 50 - len(candidates), //Earlier links are usually the site's primary feed
+			})
+		}
+	}
+	sortCandidates(candidates)
+	return candidates
+}
+
+//Whether a space separated attribute such as rel contains token
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(strings.ToLower(list)) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
+
+func sortCandidates(candidates []FeedCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
+
+//Finds feeds for pageURL. If the URL is itself a feed it is returned as the only candidate. Otherwise the page's feed links are
+//returned, plus probed common paths when Probe is set, best first. Relative links and probes are resolved against the page's final
+//URL, after redirects.
+func (d *Discoverer) Discover(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	body, page, err := d.get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if feed, err := Decode(body); err == nil {
+		return []FeedCandidate{{URL: page.String(), Title: feed.channel.title, Source: CandidateSelf, Score: 100, Verified: true, Feed: feed}}, nil
+	}
+
+	candidates := DiscoverHTML(body, page)
+	if d.Verify {
+		verified := candidates[:0]
+		for _, candidate := range candidates {
+			if d.verify(ctx, &candidate) {
+				verified = append(verified, candidate)
+			}
+		}
+		candidates = verified
+	}
+	if d.Probe {
+		known := make(map[string]bool)
+		for _, candidate := range candidates {
+			known[candidate.URL] = true
+		}
+		for _, path := range feedProbePaths {
+			probe := page.ResolveReference(&url.URL{Path: path}).String()
+			if known[probe] {
+				continue
+			}
+			candidate := FeedCandidate{URL: probe, Source: CandidateProbe, Score: 10}
+			if d.verify(ctx, &candidate) {
+				known[probe] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sortCandidates(candidates)
+	return candidates, nil
+}
+
+//Fetches and decodes a candidate, recording the result. Returns whether it turned out to be a feed.
+func (d *Discoverer) verify(ctx context.Context, candidate *FeedCandidate) bool {
+	fetcher := Fetcher{Client: d.Client, MaxSize: d.MaxSize}
+	result, err := fetcher.Fetch(ctx, candidate.URL, Validators{})
+	if err != nil || result.Feed == nil {
+		return false
+	}
+	candidate.Verified = true
+	candidate.Feed = result.Feed
+	candidate.Score += 20
+	if candidate.Title == "" {
+		candidate.Title = result.Feed.channel.title
+	}
+	return true
+}
+
+//Fetches a page, returning its body and its URL after redirects
+func (d *Discoverer) get(ctx context.Context, pageURL string) ([]byte, *url.URL, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	client := http.DefaultClient
+	if d.Client != nil {
+		client = d.Client
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, &HTTPStatusError{URL: pageURL, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	maxSize := d.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFeedSize
+	}
+	body, err := readLimited(resp.Body, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Request.URL, nil
+}
@@ -0,0 +1,123 @@
+package easyrss
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const discoverPage = `<!DOCTYPE html><html><head><meta charset=utf-8><script>if (a<b && c) {}</script>
+<LINK rel="alternate" type="application/rss+xml" title="Comments" href="comments.xml">
+<link rel="Alternate Home" type="application/atom+xml" title="Main" href="atom">
+<link rel="alternate" type="application/atom+xml" href="atom">
+<link rel="alternate" type="text/html" href="/other">
+<link rel="stylesheet" href="x.css"></head><body><p>hi &nbsp; <br></body></html>`
+
+func TestDiscoverHTML(t *testing.T) {
+	base, _ := url.Parse("http://example.com/blog/")
+	tests := []struct {
+		name string
+		html string
+		base *url.URL
+		want []string
+	}{
+		{"ranked", discoverPage, base, []string{"http://example.com/blog/atom", "http://example.com/blog/comments.xml"}},
+		{"base href", `<head><base href="/feeds/"><link rel="alternate" type="application/feed+json" href="main.json"></head>`, base,
+			[]string{"http://example.com/feeds/main.json"}},
+		{"absolute without base", `<link rel="alternate" type="application/rss+xml" href="http://x.org/rss">`, nil, []string{"http://x.org/rss"}},
+		{"no feeds", `<html><head><title>None</title></head></html>`, base, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := DiscoverHTML([]byte(tt.html), tt.base)
+			if len(candidates) != len(tt.want) {
+				t.Fatalf("candidates = %+v, want %v", candidates, tt.want)
+			}
+			for i, candidate := range candidates {
+				if candidate.URL != tt.want[i] || candidate.Source != CandidateLink || candidate.Verified {
+					t.Errorf("candidate %d = %+v, want %s", i, candidate, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+//Serves discoverPage under /blog/ after a redirect from /old, with feeds at /blog/atom and /rss.xml
+func discoverServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/blog/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/blog/", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, discoverPage) })
+	mux.HandleFunc("/blog/atom", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, atomFeed) })
+	mux.HandleFunc("/rss.xml", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, encodePlainFeed) })
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDiscover(t *testing.T) {
+	srv := discoverServer(t)
+	type want struct {
+		url, source string
+		verified    bool
+	}
+	tests := []struct {
+		name string
+		d    Discoverer
+		page string
+		want []want
+	}{
+		{"links", Discoverer{}, "/blog/", []want{{"/blog/atom", CandidateLink, false}, {"/blog/comments.xml", CandidateLink, false}}},
+		{"links after redirect", Discoverer{}, "/old", []want{{"/blog/atom", CandidateLink, false}, {"/blog/comments.xml", CandidateLink, false}}},
+		{"verified", Discoverer{Verify: true}, "/old", []want{{"/blog/atom", CandidateLink, true}}},
+		{"probed", Discoverer{Verify: true, Probe: true}, "/old", []want{{"/blog/atom", CandidateLink, true}, {"/rss.xml", CandidateProbe, true}}},
+		{"self", Discoverer{}, "/blog/atom", []want{{"/blog/atom", CandidateSelf, true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := tt.d.Discover(context.Background(), srv.URL+tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(candidates) != len(tt.want) {
+				t.Fatalf("candidates = %+v, want %+v", candidates, tt.want)
+			}
+			for i, candidate := range candidates {
+				w := tt.want[i]
+				if candidate.URL != srv.URL+w.url || candidate.Source != w.source || candidate.Verified != w.verified {
+					t.Errorf("candidate %d = %+v, want %+v", i, candidate, w)
+				}
+				if candidate.Verified && candidate.Feed == nil {
+					t.Errorf("candidate %d is verified without a feed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	srv := discoverServer(t)
+	var statusErr *HTTPStatusError
+	if _, err := (&Discoverer{}).Discover(context.Background(), srv.URL+"/missing"); !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("404 error = %v", err)
+	}
+	big := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html><head>"+strings.Repeat("<meta name=x>", 10000)+"</head></html>")
+	}))
+	defer big.Close()
+	if _, err := (&Discoverer{MaxSize: 64 << 10}).Discover(context.Background(), big.URL); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("error = %v, want ErrFeedTooLarge", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&Discoverer{}).Discover(ctx, srv.URL+"/blog/"); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled error = %v", err)
+	}
+}
@@ -0,0 +1,639 @@
+package easyrss
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Escapes character data and attribute values
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+
+//Minimal indenting XML writer. Errors are sticky inside the underlying bufio.Writer and surface on flush.
+type xmlWriter struct {
+	w     *bufio.Writer
+	depth int
+}
+
+func (x *xmlWriter) indent() {
+	x.w.WriteString(strings.Repeat("\t", x.depth))
+}
+
+//Writes name="value" pairs, skipping attributes with empty values
+func (x *xmlWriter) attrs(attrs []string) {
+	for a := 0; a+1 < len(attrs); a += 2 {
+		if attrs[a+1] == "" {
+			continue
+		}
+		x.w.WriteString(" " + attrs[a] + "=\"" + xmlEscaper.Replace(attrs[a+1]) + "\"")
+	}
+}
+
+//Opens an element that will contain child elements
+func (x *xmlWriter) start(name string, attrs ...string) {
+	x.indent()
+	x.w.WriteString("<" + name)
+	x.attrs(attrs)
+	x.w.WriteString(">\n")
+	x.depth++
+}
+
+//Closes an element opened with start
+func (x *xmlWriter) end(name string) {
+	x.depth--
+	x.indent()
+	x.w.WriteString("</" + name + ">\n")
+}
+
+//Writes a text-only element. Nothing is written if the content is empty.
+func (x *xmlWriter) elem(name string, content string, attrs ...string) {
+	if content == "" {
+		return
+	}
+	x.indent()
+	x.w.WriteString("<" + name)
+	x.attrs(attrs)
+	x.w.WriteString(">" + xmlEscaper.Replace(content) + "</" + name + ">\n")
+}
+
+//Writes a self-closing element
+func (x *xmlWriter) empty(name string, attrs ...string) {
+	x.indent()
+	x.w.WriteString("<" + name)
+	x.attrs(attrs)
+	x.w.WriteString("/>\n")
+}
+
+//Pass in an *RSS, get an RSS 2.0 document back. Extension namespaces are only declared when the feed uses them. Atom feeds are converted to RSS 2.0.
+func Encode(r *RSS) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeTo(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//Same as Encode, but writes the document to w.
+func EncodeTo(w io.Writer, r *RSS) error {
+	if r == nil {
+		return ErrNilFeed
+	}
+	x := &xmlWriter{w: bufio.NewWriter(w)}
+	c := &r.channel
+	isItunes, isMRSS, hasContent, isPodcast := c.isItunes, c.isMRSS, false, c.isPodcast
+	uses := func(item *Item) {
+		isItunes = isItunes || item.isItunes
+		isMRSS = isMRSS || item.isMRSS
+		hasContent = hasContent || item.content != ""
+		isPodcast = isPodcast || item.isPodcast
+	}
+	for itemID := range c.items {
+		uses(&c.items[itemID])
+	}
+	for liveID := range c.liveItems {
+		uses(&c.liveItems[liveID].Item)
+	}
+	isPodcast = isPodcast || len(c.liveItems) > 0
+
+	x.w.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	rssAttrs := []string{"version", "2.0"}
+	if isItunes {
+		rssAttrs = append(rssAttrs, "xmlns:itunes", itunesNS)
+	}
+	if isMRSS {
+		rssAttrs = append(rssAttrs, "xmlns:media", mrssNS)
+	}
+	if hasContent {
+		rssAttrs = append(rssAttrs, "xmlns:content", contentNS)
+	}
+	if isPodcast {
+		rssAttrs = append(rssAttrs, "xmlns:podcast", podcastNS)
+	}
+	x.start("rss", rssAttrs...)
+	x.start("channel")
+	x.elem("title", c.title)
+	x.elem("link", c.link)
+	x.elem("description", c.description)
+	x.elem("language", c.language)
+	x.elem("copyright", c.copyright)
+	x.elem("generator", c.generator)
+	for _, category := range c.categories {
+		x.elem("category", category)
+	}
+	writeChannelMeta(x, c)
+	if c.isItunes {
+		writeItunesMeta(x, &c.itunes)
+	}
+	if c.isMRSS {
+		writeMediaChannelMeta(x, &c.media)
+	}
+	if c.isPodcast {
+		writePodcastMeta(x, &c.podcast)
+	}
+	for itemID := range c.items {
+		writeItem(x, &c.items[itemID], "item")
+	}
+	for _, live := range c.liveItems {
+		start, end := "", ""
+		if live.start != nil {
+			start = live.start.Format(time.RFC3339)
+		}
+		if live.end != nil {
+			end = live.end.Format(time.RFC3339)
+		}
+		writeItem(x, &live.Item, "podcast:liveItem", "status", live.status, "start", start, "end", end)
+	}
+	x.end("channel")
+	x.end("rss")
+	return x.w.Flush()
+}
+
+//Writes the optional RSS 2.0 channel elements
+func writeChannelMeta(x *xmlWriter, c *Channel) {
+	x.elem("managingEditor", c.editor)
+	x.elem("webMaster", c.webMaster)
+	if c.pubDate != nil {
+		x.elem("pubDate", c.pubDate.Format(time.RFC1123Z))
+	}
+	if c.lastBuild != nil {
+		x.elem("lastBuildDate", c.lastBuild.Format(time.RFC1123Z))
+	}
+	x.elem("docs", c.docs)
+	if c.cloud != nil {
+		port := ""
+		if c.cloud.Port != 0 {
+			port = strconv.Itoa(c.cloud.Port)
+		}
+		x.empty("cloud", "domain", c.cloud.Domain, "port", port, "path", c.cloud.Path, "registerProcedure", c.cloud.RegisterProcedure, "protocol", c.cloud.Protocol)
+	}
+	if c.ttl > 0 {
+		x.elem("ttl", strconv.FormatInt(int64(c.ttl/time.Minute), 10))
+	}
+	if c.image.url != "" {
+		title, link := c.image.title, c.image.link
+		if title == "" { //Required by RSS 2.0 but absent from Atom logos
+			title = c.title
+		}
+		if link == "" {
+			link = c.link
+		}
+		x.start("image")
+		x.elem("url", c.image.url)
+		x.elem("title", title)
+		x.elem("link", link)
+		if c.image.width != 0 {
+			x.elem("width", strconv.Itoa(c.image.width))
+		}
+		if c.image.height != 0 {
+			x.elem("height", strconv.Itoa(c.image.height))
+		}
+		x.elem("description", c.image.description)
+		x.end("image")
+	}
+	x.elem("rating", c.rating)
+	if t := c.textInput; t != nil {
+		x.start("textInput")
+		x.elem("title", t.Title)
+		x.elem("description", t.Description)
+		x.elem("name", t.Name)
+		x.elem("link", t.Link)
+		x.end("textInput")
+	}
+	if c.skipHours != 0 {
+		x.start("skipHours")
+		for _, hour := range c.skipHours.Hours() {
+			x.elem("hour", strconv.Itoa(hour))
+		}
+		x.end("skipHours")
+	}
+	if c.skipDays != 0 {
+		x.start("skipDays")
+		for _, day := range c.skipDays.Days() {
+			x.elem("day", day.String())
+		}
+		x.end("skipDays")
+	}
+}
+
+//Writes an item as the given element. Live items share everything but the element name and scheduling attributes.
+func writeItem(x *xmlWriter, i *Item, name string, attrs ...string) {
+	x.start(name, attrs...)
+	x.elem("title", i.title)
+	x.elem("link", i.link)
+	x.elem("description", i.description)
+	x.elem("author", i.author)
+	for _, category := range i.categories {
+		x.elem("category", category.Value, "domain", category.Domain)
+	}
+	if i.guid.Content != "" {
+		x.elem("guid", i.guid.Content, "isPermaLink", strconv.FormatBool(i.guid.IsPermaLink))
+	}
+	if i.date != nil {
+		x.elem("pubDate", i.date.Format(time.RFC1123Z))
+	}
+	x.elem("comments", i.comments)
+	if i.source != nil {
+		x.elem("source", i.source.Title, "url", i.source.URL)
+	}
+	if i.hasEnclosure {
+		x.empty("enclosure", "url", i.enclosure.url, "length", strconv.FormatUint(i.enclosure.size, 10), "type", i.enclosure.mediaType)
+	}
+	x.elem("content:encoded", i.content)
+	if i.isItunes {
+		writeItunesMeta(x, &i.itunes)
+	}
+	if i.isMRSS {
+		writeMediaMeta(x, &i.media)
+	}
+	if i.isPodcast {
+		writePodcastMeta(x, &i.podcast)
+	}
+	x.end(name)
+}
+
+//Writes the populated Itunes fields. Shared between channels and items.
+func writeItunesMeta(x *xmlWriter, m *ItunesMeta) {
+	x.elem("itunes:author", m.author)
+	x.elem("itunes:subtitle", m.subtitle)
+	x.elem("itunes:summary", m.summary)
+	x.elem("itunes:explicit", m.explicit)
+	x.elem("itunes:keywords", strings.Join(m.keywords, ","))
+	if m.image.url != "" {
+		x.empty("itunes:image", "href", m.image.url)
+	}
+	if m.duration > 0 {
+		x.elem("itunes:duration", formatItunesDuration(m.duration))
+	}
+	for _, category := range m.categories {
+		writeItunesCategory(x, category)
+	}
+	if m.owner != nil {
+		x.start("itunes:owner")
+		x.elem("itunes:name", m.owner.Name)
+		x.elem("itunes:email", m.owner.Email)
+		x.end("itunes:owner")
+	}
+	x.elem("itunes:type", m.podcastType)
+	x.elem("itunes:title", m.title)
+	if m.episode != 0 {
+		x.elem("itunes:episode", strconv.Itoa(m.episode))
+	}
+	if m.season != 0 {
+		x.elem("itunes:season", strconv.Itoa(m.season))
+	}
+	x.elem("itunes:episodeType", m.episodeType)
+	if m.block {
+		x.elem("itunes:block", "Yes")
+	}
+	if m.complete {
+		x.elem("itunes:complete", "Yes")
+	}
+	x.elem("itunes:new-feed-url", m.newFeedURL)
+}
+
+func writeItunesCategory(x *xmlWriter, c ItunesCategory) {
+	if len(c.Subcategories) == 0 {
+		x.empty("itunes:category", "text", c.Text)
+		return
+	}
+	x.start("itunes:category", "text", c.Text)
+	for _, sub := range c.Subcategories {
+		writeItunesCategory(x, sub)
+	}
+	x.end("itunes:category")
+}
+
+//Formats a duration as H:MM:SS, the most widely understood Itunes duration format
+func formatItunesDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+//Writes the populated Podcasting 2.0 fields. Shared between channels and items.
+func writePodcastMeta(x *xmlWriter, p *PodcastMeta) {
+	if p.locked != nil {
+		locked := "no"
+		if p.locked.Locked {
+			locked = "yes"
+		}
+		x.elem("podcast:locked", locked, "owner", p.locked.Owner)
+	}
+	x.elem("podcast:guid", p.guid)
+	x.elem("podcast:medium", p.medium)
+	for _, funding := range p.funding {
+		x.elem("podcast:funding", funding.Text, "url", funding.URL)
+	}
+	for _, person := range p.persons {
+		x.elem("podcast:person", person.Name, "role", person.Role, "group", person.Group, "img", person.Image, "href", person.Href)
+	}
+	if p.location != nil {
+		x.elem("podcast:location", p.location.Name, "geo", p.location.Geo, "osm", p.location.OSM)
+	}
+	if p.license != nil {
+		x.elem("podcast:license", p.license.Identifier, "url", p.license.URL)
+	}
+	for _, trailer := range p.trailers {
+		pubDate, length, season := "", "", ""
+		if trailer.PubDate != nil {
+			pubDate = trailer.PubDate.Format(time.RFC1123Z)
+		}
+		if trailer.Length != 0 {
+			length = strconv.FormatUint(trailer.Length, 10)
+		}
+		if trailer.Season != 0 {
+			season = strconv.Itoa(trailer.Season)
+		}
+		x.elem("podcast:trailer", trailer.Title, "url", trailer.URL, "pubdate", pubDate, "length", length, "type", trailer.Type, "season", season)
+	}
+	for _, transcript := range p.transcripts {
+		x.empty("podcast:transcript", "url", transcript.URL, "type", transcript.Type, "language", transcript.Language, "rel", transcript.Rel)
+	}
+	if p.chapters != nil {
+		x.empty("podcast:chapters", "url", p.chapters.URL, "type", p.chapters.Type)
+	}
+	for _, alt := range p.alternateEnclosures {
+		writeAlternateEnclosure(x, &alt)
+	}
+	for _, link := range p.contentLinks {
+		x.elem("podcast:contentLink", link.Text, "href", link.Href)
+	}
+	for v := range p.values {
+		writePodcastValue(x, &p.values[v])
+	}
+}
+
+func writeAlternateEnclosure(x *xmlWriter, a *AlternateEnclosure) {
+	length, bitrate, height, isDefault := "", "", "", ""
+	if a.Length != 0 {
+		length = strconv.FormatUint(a.Length, 10)
+	}
+	if a.Bitrate != 0 {
+		bitrate = strconv.FormatFloat(a.Bitrate, 'f', -1, 64)
+	}
+	if a.Height != 0 {
+		height = strconv.Itoa(a.Height)
+	}
+	if a.Default {
+		isDefault = "true"
+	}
+	x.start("podcast:alternateEnclosure", "type", a.Type, "length", length, "bitrate", bitrate, "height", height, "lang", a.Lang,
+		"title", a.Title, "rel", a.Rel, "codecs", a.Codecs, "default", isDefault)
+	for _, source := range a.Sources {
+		x.empty("podcast:source", "uri", source.URI, "contentType", source.ContentType)
+	}
+	if a.Integrity != nil {
+		x.empty("podcast:integrity", "type", a.Integrity.Type, "value", a.Integrity.Value)
+	}
+	x.end("podcast:alternateEnclosure")
+}
+
+func writePodcastValue(x *xmlWriter, v *Value) {
+	x.start("podcast:value", "type", v.Type, "method", v.Method, "suggested", v.Suggested)
+	writeValueRecipients(x, v.Recipients)
+	for _, split := range v.TimeSplits {
+		percentage := ""
+		if split.RemotePercentage != 100 {
+			percentage = strconv.Itoa(split.RemotePercentage)
+		}
+		startTime := strconv.FormatFloat(split.Start.Seconds(), 'f', -1, 64) //Required, so written even when the split starts at 0
+		x.start("podcast:valueTimeSplit", "startTime", startTime, "duration", formatSeconds(split.Duration),
+			"remoteStartTime", formatSeconds(split.RemoteStart), "remotePercentage", percentage)
+		if remote := split.RemoteItem; remote != nil {
+			x.empty("podcast:remoteItem", "feedGuid", remote.FeedGUID, "feedUrl", remote.FeedURL, "itemGuid", remote.ItemGUID, "medium", remote.Medium)
+		}
+		writeValueRecipients(x, split.Recipients)
+		x.end("podcast:valueTimeSplit")
+	}
+	x.end("podcast:value")
+}
+
+func writeValueRecipients(x *xmlWriter, recipients []ValueRecipient) {
+	for _, r := range recipients {
+		fee := ""
+		if r.Fee {
+			fee = "true"
+		}
+		x.empty("podcast:valueRecipient", "name", r.Name, "type", r.Type, "address", r.Address, "customKey", r.CustomKey,
+			"customValue", r.CustomValue, "split", strconv.Itoa(r.Split), "fee", fee)
+	}
+}
+
+//Formats a duration as decimal seconds, or an empty string for zero so the attribute is left out
+func formatSeconds(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+//Formats an integer attribute, or an empty string for zero so the attribute is left out
+func formatInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+//Formats a decimal attribute, or an empty string for zero so the attribute is left out
+func formatFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func writeMediaThumbnail(x *xmlWriter, i *Image, attrs ...string) {
+	if i.url == "" {
+		return
+	}
+	x.empty("media:thumbnail", append([]string{"url", i.url, "width", formatInt(i.width), "height", formatInt(i.height)}, attrs...)...)
+}
+
+func writeMediaChannelMeta(x *xmlWriter, m *MediaChannelMeta) {
+	x.elem("media:rating", m.rating)
+	x.elem("media:copyright", m.copyright)
+	writeMediaThumbnail(x, &m.thumbnail)
+	x.elem("media:keywords", strings.Join(m.keywords, ", "))
+	for _, category := range m.categories {
+		x.elem("media:category", category)
+	}
+	for r := range m.restrictions {
+		writeMediaRestriction(x, &m.restrictions[r])
+	}
+}
+
+func writeMediaCommunity(x *xmlWriter, c *MediaCommunity) {
+	x.start("media:community")
+	if c.StarAverage != 0 || c.StarCount != 0 {
+		x.empty("media:starRating", "average", formatFloat(c.StarAverage), "count", formatInt(c.StarCount), "min", formatFloat(c.StarMin), "max", formatFloat(c.StarMax))
+	}
+	if c.Views != 0 || c.Favorites != 0 {
+		x.empty("media:statistics", "views", strconv.FormatUint(c.Views, 10), "favorites", strconv.FormatUint(c.Favorites, 10))
+	}
+	tags := make([]string, len(c.Tags))
+	for t, tag := range c.Tags {
+		tags[t] = tag.Name
+		if tag.Weight != 1 {
+			tags[t] += ":" + strconv.Itoa(tag.Weight)
+		}
+	}
+	x.elem("media:tags", strings.Join(tags, ", "))
+	x.end("media:community")
+}
+
+func writeMediaRestriction(x *xmlWriter, r *MediaRestriction) {
+	if len(r.Values) == 0 { //Sharing restrictions have no content
+		x.empty("media:restriction", "relationship", r.Relationship, "type", r.Type)
+		return
+	}
+	x.elem("media:restriction", strings.Join(r.Values, " "), "relationship", r.Relationship, "type", r.Type)
+}
+
+func writeMediaContent(x *xmlWriter, c *MediaContent) {
+	fileSize, isDefault, expression := "", "", ""
+	if c.FileSize > 0 {
+		fileSize = strconv.FormatUint(c.FileSize, 10)
+	}
+	if c.IsDefault {
+		isDefault = "true"
+	}
+	if c.Expression != "full" {
+		expression = c.Expression
+	}
+	attrs := []string{"url", c.URL, "fileSize", fileSize, "type", c.Type, "medium", c.Medium,
+		"isDefault", isDefault, "expression", expression, "bitrate", formatFloat(c.Bitrate), "framerate", formatFloat(c.Framerate),
+		"samplingrate", formatFloat(c.SamplingRate), "channels", formatInt(c.Channels), "duration", formatSeconds(c.Duration),
+		"height", formatInt(c.Height), "width", formatInt(c.Width), "lang", c.Lang}
+	if c.MediaDetails.isEmpty() {
+		x.empty("media:content", attrs...)
+		return
+	}
+	x.start("media:content", attrs...)
+	writeMediaDetails(x, &c.MediaDetails)
+	x.end("media:content")
+}
+
+//Writes the optional elements shared by items, groups and content objects
+func writeMediaDetails(x *xmlWriter, d *MediaDetails) {
+	textType := func(t string) string {
+		if t == "plain" {
+			return ""
+		}
+		return t
+	}
+	x.elem("media:title", d.Title, "type", textType(d.TitleType))
+	x.elem("media:description", d.Description, "type", textType(d.DescriptionType))
+	x.elem("media:keywords", strings.Join(d.Keywords, ", "))
+	for t := range d.Thumbnails {
+		writeMediaThumbnail(x, &d.Thumbnails[t].Image, "time", formatSeconds(d.Thumbnails[t].Time))
+	}
+	if d.Player != nil {
+		x.empty("media:player", "url", d.Player.URL, "width", formatInt(d.Player.Width), "height", formatInt(d.Player.Height))
+	}
+	if d.Embed != nil {
+		x.start("media:embed", "url", d.Embed.URL, "width", formatInt(d.Embed.Width), "height", formatInt(d.Embed.Height))
+		names := make([]string, 0, len(d.Embed.Params))
+		for name := range d.Embed.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			x.elem("media:param", d.Embed.Params[name], "name", name)
+		}
+		x.end("media:embed")
+	}
+	for _, text := range d.Texts {
+		x.elem("media:text", text.Text, "type", textType(text.Type), "lang", text.Lang, "start", formatSeconds(text.Start), "end", formatSeconds(text.End))
+	}
+	for _, subTitle := range d.SubTitles {
+		x.empty("media:subTitle", "type", subTitle.Type, "lang", subTitle.Lang, "href", subTitle.URL)
+	}
+	for _, rating := range d.Ratings {
+		scheme := rating.Scheme
+		if scheme == "urn:simple" {
+			scheme = ""
+		}
+		x.elem("media:rating", rating.Value, "scheme", scheme)
+	}
+	for r := range d.Restrictions {
+		writeMediaRestriction(x, &d.Restrictions[r])
+	}
+	if d.License != nil {
+		x.elem("media:license", d.License.Name, "type", d.License.Type, "href", d.License.Href)
+	}
+	if d.Copyright != nil {
+		x.elem("media:copyright", d.Copyright.Text, "url", d.Copyright.URL)
+	}
+	if d.Status != nil {
+		x.empty("media:status", "state", d.Status.State, "reason", d.Status.Reason)
+	}
+	for _, price := range d.Prices {
+		amount := ""
+		if price.Price != 0 {
+			amount = strconv.FormatFloat(price.Price, 'f', -1, 64)
+		}
+		x.empty("media:price", "type", price.Type, "price", amount, "currency", price.Currency, "info", price.Info)
+	}
+	if d.Community != nil {
+		writeMediaCommunity(x, d.Community)
+	}
+	for _, mediaHash := range d.Hashes {
+		algorithm := mediaHash.Algorithm
+		if algorithm == "md5" {
+			algorithm = ""
+		}
+		x.elem("media:hash", mediaHash.Value, "algo", algorithm)
+	}
+	for _, peerLink := range d.PeerLinks {
+		x.empty("media:peerLink", "type", peerLink.Type, "href", peerLink.URL)
+	}
+	for _, location := range d.Locations {
+		attrs := []string{"description", location.Description, "start", formatSeconds(location.Start), "end", formatSeconds(location.End)}
+		if location.Point == nil {
+			x.empty("media:location", attrs...)
+			continue
+		}
+		x.start("media:location", attrs...)
+		x.elem("georss:point", strconv.FormatFloat(location.Point.Lat, 'f', -1, 64)+" "+strconv.FormatFloat(location.Point.Lon, 'f', -1, 64), "xmlns:georss", georssNS)
+		x.end("media:location")
+	}
+	if len(d.Scenes) > 0 {
+		x.start("media:scenes")
+		for _, scene := range d.Scenes {
+			x.start("media:scene")
+			x.elem("media:sceneTitle", scene.Title)
+			x.elem("media:sceneDescription", scene.Description)
+			x.elem("media:sceneStartTime", formatSeconds(scene.Start))
+			x.elem("media:sceneEndTime", formatSeconds(scene.End))
+			x.end("media:scene")
+		}
+		x.end("media:scenes")
+	}
+	for _, credit := range d.Credits {
+		scheme := credit.Scheme
+		if scheme == "urn:ebu" {
+			scheme = ""
+		}
+		x.elem("media:credit", credit.Name, "role", credit.Role, "scheme", scheme)
+	}
+}
+
+func writeMediaMeta(x *xmlWriter, m *MediaMeta) {
+	for c := range m.contents {
+		writeMediaContent(x, &m.contents[c])
+	}
+	for _, group := range m.groups {
+		x.start("media:group")
+		for c := range group.Contents {
+			writeMediaContent(x, &group.Contents[c])
+		}
+		writeMediaDetails(x, &group.MediaDetails)
+		x.end("media:group")
+	}
+	writeMediaDetails(x, &m.details)
+}
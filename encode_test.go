@@ -0,0 +1,152 @@
+package easyrss
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const encodePlainFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Plain &amp; simple</title><link>http://example.com/</link><description>D</description>
+<language>en-us</language><copyright>(c) Example</copyright><generator>gen</generator><category>News</category><category>Go</category>
+<item><title>First</title><link>http://example.com/1</link><description>&lt;p&gt;Hello&lt;/p&gt;</description>
+<pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate><enclosure url="http://example.com/1.mp3" length="1234" type="audio/mpeg"/></item>
+<item><title>Second</title><guid isPermaLink="false">tag:2</guid></item></channel></rss>`
+
+const encodeItunesFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel><title>Cast</title><link>http://example.com/</link><description>D</description>
+<itunes:author>Bob</itunes:author><itunes:explicit>no</itunes:explicit><itunes:image href="http://example.com/i.png"/>
+<item><title>Ep 1</title><itunes:duration>1:02:03</itunes:duration><content:encoded><![CDATA[<b>full</b>]]></content:encoded></item>
+</channel></rss>`
+
+const encodeMRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>Video</title><link>http://example.com/</link>
+<description>D</description><media:rating>adult</media:rating><media:copyright>(c) Studio</media:copyright>
+<item><title>Clip</title><media:content url="http://example.com/v.mp4" fileSize="99" type="video/mp4" height="720"/>
+<media:thumbnail url="http://example.com/t.jpg" width="120" height="90"/></item></channel></rss>`
+
+func TestEncodeNamespaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		feed    string
+		want    []string
+		notWant []string
+	}{
+		{"plain", encodePlainFeed, nil, []string{"xmlns:itunes", "xmlns:media", "xmlns:content", "xmlns:podcast"}},
+		{"itunes", encodeItunesFeed, []string{`xmlns:itunes="` + itunesNS + `"`, `xmlns:content="` + contentNS + `"`}, []string{"xmlns:media"}},
+		{"mrss", encodeMRSSFeed, []string{`xmlns:media="` + mrssNS + `"`}, []string{"xmlns:itunes", "xmlns:content"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Decode([]byte(tt.feed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := Encode(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !bytes.Contains(out, []byte(want)) {
+					t.Errorf("missing %s in\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if bytes.Contains(out, []byte(notWant)) {
+					t.Errorf("unexpected %s in\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
+
+//Decoding an encoded feed must give back the fields of the source document, and encoding it again the same document
+func TestEncodeRoundTrip(t *testing.T) {
+	for name, feed := range map[string]string{"plain": encodePlainFeed, "itunes": encodeItunesFeed, "mrss": encodeMRSSFeed} {
+		t.Run(name, func(t *testing.T) {
+			r, err := Decode([]byte(feed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			first, err := Encode(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := Decode(first)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, first)
+			}
+			if !reflect.DeepEqual(decoded, r) {
+				t.Errorf("round trip lost fields\ngot:  %+v\nwant: %+v", decoded.channel, r.channel)
+			}
+			second, err := Encode(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Errorf("round trip changed the feed\nfirst:\n%s\nsecond:\n%s", first, second)
+			}
+		})
+	}
+}
+
+func TestEncodeFields(t *testing.T) {
+	r, err := Decode([]byte(encodePlainFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"title", r.Title, "Plain & simple"},
+		{"link", r.Link, "http://example.com/"},
+		{"description", r.Description, "D"},
+		{"language", r.Language, "en-us"},
+		{"copyright", r.Copyright, "(c) Example"},
+		{"generator", r.Generator, "gen"},
+	}
+	for _, tt := range channel {
+		if got, err := tt.get(); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if categories, _ := r.Categories(); strings.Join(categories, ",") != "News,Go" {
+		t.Errorf("categories = %v", categories)
+	}
+	items, err := r.Items()
+	if err != nil || len(items) != 2 {
+		t.Fatal(items, err)
+	}
+	if d, err := items[0].Description(); err != nil || d != "<p>Hello</p>" {
+		t.Errorf("description = %q, %v", d, err)
+	}
+	if d, err := items[0].Date(); err != nil || !d.Equal(time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)) {
+		t.Errorf("date = %v, %v", d, err)
+	}
+	if !items[0].HasEnclosure() || items[0].EnclosureURL() != "http://example.com/1.mp3" || items[0].enclosure.size != 1234 ||
+		items[0].enclosure.mediaType != "audio/mpeg" {
+		t.Errorf("enclosure = %+v", items[0].enclosure)
+	}
+	if g, err := items[1].GUID(); err != nil || g.IsPermaLink || g.Content != "tag:2" {
+		t.Errorf("guid = %+v, %v", g, err)
+	}
+}
+
+func TestEncodeNil(t *testing.T) {
+	if _, err := Encode(nil); err != ErrNilFeed {
+		t.Fatalf("Encode(nil) error = %v, want ErrNilFeed", err)
+	}
+}
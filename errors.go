@@ -0,0 +1,82 @@
+package easyrss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Sentinel errors. Check for them with errors.Is rather than by comparing messages.
+var (
+	ErrFieldNotPopulated    = errors.New("Field is not populated")            //Wrapped by every *FieldError
+	ErrNotItunes            = errors.New("Not an Itunes RSS Feed")            //Itunes accessor called on a feed or item without Itunes Extensions
+	ErrNotPodcast           = errors.New("Not a Podcasting 2.0 Feed")         //Podcasting 2.0 accessor called on a feed or item without Podcasting 2.0 Extensions
+	ErrNotMRSS              = errors.New("Not a MediaRSS Feed")               //MediaRSS accessor called on a feed or item without MediaRSS Extensions
+	ErrNoItems              = errors.New("Feed contains no items")            //Returned by Items for an empty feed
+	ErrNotFeed              = errors.New("Document is not a recognized feed") //Input is not RSS, Atom or JSON Feed
+	ErrNilFeed              = errors.New("Cannot encode a nil feed")
+	ErrBadTranscript        = errors.New("Malformed transcript")          //Transcript parsers could not make sense of a cue
+	ErrUnsupportedFormat    = errors.New("Unsupported transcript format") //No parser for the transcript's MIME type
+	ErrBadChapters          = errors.New("Malformed chapters file")
+	ErrInvalidValue         = errors.New("Invalid value block") //Value.Validate found splits that can't be paid out
+	ErrNoRendition          = errors.New("No rendition matches the constraints")
+	ErrIntegrityMismatch    = errors.New("Content doesn't match its integrity hash")
+	ErrUnsupportedIntegrity = errors.New("Unsupported integrity check")    //Neither an SRI hash with a known algorithm nor anything else we can verify
+	ErrBadDate              = errors.New("Unrecognized date format")       //Returned by ParseDate
+	ErrUnsupportedEncoding  = errors.New("Unsupported character encoding") //The feed is encoded as UTF-32, which the pure-Go parser can't decode
+	ErrFeedTooLarge         = errors.New("Feed exceeds the maximum size")  //Fetcher or Discoverer read more than their MaxSize
+)
+
+//Elements reported by FieldError
+const (
+	ElementChannel      = "channel"
+	ElementItem         = "item"
+	ElementImage        = "image"
+	ElementLiveItem     = "liveItem"
+	ElementMediaContent = "mediaContent" //A MediaRSS <media:content> object
+)
+
+//Namespaces reported by FieldError. Core RSS fields have an empty namespace.
+const (
+	NamespaceItunes  = "itunes"
+	NamespaceMRSS    = "media"
+	NamespacePodcast = "podcast"
+)
+
+//Returned by accessors when the requested field is absent. errors.Is(err, ErrFieldNotPopulated) holds for every FieldError, use
+//errors.As to find out which field was missing.
+type FieldError struct {
+	Element   string //One of ElementChannel, ElementItem, ElementImage, ElementLiveItem or ElementMediaContent
+	Namespace string //Namespace of the field, e.g. NamespaceItunes. Empty for core RSS fields.
+	Field     string //Name of the field, e.g. "title" or "duration"
+}
+
+func (e *FieldError) Error() string {
+	field := e.Field
+	if e.Namespace != "" {
+		field = e.Namespace + ":" + field
+	}
+	if e.Element == "" {
+		return "Field " + field + " is not populated"
+	}
+	return strings.ToUpper(e.Element[:1]) + e.Element[1:] + " " + field + " is not populated"
+}
+
+func (e *FieldError) Unwrap() error {
+	return ErrFieldNotPopulated
+}
+
+func notPopulated(element, namespace, field string) error {
+	return &FieldError{Element: element, Namespace: namespace, Field: field}
+}
+
+//Returned by Fetcher and Discoverer when a server replies with an unexpected HTTP status.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("Fetching %s failed with HTTP status %s", e.URL, e.Status)
+}
@@ -0,0 +1,64 @@
+package easyrss
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFieldError(t *testing.T) {
+	tests := []struct {
+		err  *FieldError
+		want string
+	}{
+		{&FieldError{Element: ElementChannel, Field: "title"}, "Channel title is not populated"},
+		{&FieldError{Element: ElementItem, Namespace: NamespaceItunes, Field: "duration"}, "Item itunes:duration is not populated"},
+		{&FieldError{Element: ElementLiveItem, Namespace: NamespacePodcast, Field: "title"}, "LiveItem podcast:title is not populated"},
+		{&FieldError{Field: "guid"}, "Field guid is not populated"},
+		{&FieldError{}, "Field  is not populated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, ErrFieldNotPopulated) {
+				t.Error("FieldError does not unwrap to ErrFieldNotPopulated")
+			}
+		})
+	}
+}
+
+//Accessors on an empty feed report which field is missing
+func TestAccessorErrors(t *testing.T) {
+	r, err := Decode([]byte(`<rss version="2.0"><channel><item><link>http://x</link></item></channel></rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := r.Items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		get     func() error
+		element string
+		field   string
+	}{
+		{"channel title", func() error { _, err := r.Title(); return err }, ElementChannel, "title"},
+		{"item title", func() error { _, err := items[0].Title(); return err }, ElementItem, "title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldErr *FieldError
+			if err := tt.get(); !errors.As(err, &fieldErr) || fieldErr.Element != tt.element || fieldErr.Field != tt.field {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+	if _, err := r.ItunesAuthor(); !errors.Is(err, ErrNotItunes) {
+		t.Errorf("ItunesAuthor error = %v, want ErrNotItunes", err)
+	}
+	if _, err := (&RSS{}).Items(); !errors.Is(err, ErrNoItems) {
+		t.Errorf("Items error = %v, want ErrNoItems", err)
+	}
+}
@@ -0,0 +1,162 @@
+package easyrss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//Largest feed a Fetcher accepts unless told otherwise
+const DefaultMaxFeedSize = 64 << 20
+
+//Fetches and decodes feeds over HTTP with conditional GET support. The zero value is ready to use and goes through http.DefaultClient.
+type Fetcher struct {
+	Client    *http.Client //Client used for requests. If nil, http.DefaultClient is used.
+	UserAgent string       //Sent as the User-Agent header when set
+	MaxSize   int64        //Largest feed accepted, in bytes, both as sent and once decompressed. If zero, DefaultMaxFeedSize is used.
+}
+
+//HTTP caching validators from a previous fetch. Pass them back to Fetch to only download the feed when it has changed.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+//The outcome of a Fetch.
+type FetchResult struct {
+	Changed      bool       //False when the server replied 304 Not Modified, in which case Feed is nil
+	Feed         *RSS       //The decoded feed when Changed is set
+	Validators   Validators //Validators to pass to the next Fetch of this feed
+	StatusCode   int        //HTTP status of the final response
+	URL          string     //Final URL after following redirects
+	PermanentURL string     //Set when the feed has permanently moved (301 or 308). Subscriptions should be updated to it.
+}
+
+//Fetches feedURL and decodes it. Cached validators are sent as If-None-Match and If-Modified-Since, gzip and deflate responses are
+//decompressed, and redirects are followed while keeping track of permanent moves.
+func (f *Fetcher) Fetch(ctx context.Context, feedURL string, cached Validators) (*FetchResult, error) {
+	req, err := http.NewRequest("GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	client := http.DefaultClient
+	if f.Client != nil {
+		client = f.Client
+	}
+	//Only an unbroken chain of permanent redirects from the original URL counts as a permanent move
+	permanentURL := ""
+	allPermanent := true
+	redirectClient := *client
+	redirectClient.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if status := next.Response.StatusCode; allPermanent && (status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect) {
+			permanentURL = next.URL.String()
+		} else {
+			allPermanent = false
+		}
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(next, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("Stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	resp, err := redirectClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	result := &FetchResult{
+		StatusCode:   resp.StatusCode,
+		URL:          resp.Request.URL.String(),
+		PermanentURL: permanentURL,
+		Validators:   Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")},
+	}
+	if resp.StatusCode == http.StatusNotModified { //Servers may omit unchanged validators from a 304
+		if result.Validators.ETag == "" {
+			result.Validators.ETag = cached.ETag
+		}
+		if result.Validators.LastModified == "" {
+			result.Validators.LastModified = cached.LastModified
+		}
+		return result, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &HTTPStatusError{URL: feedURL, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	maxSize := f.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFeedSize
+	}
+	body, err := readLimited(resp.Body, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if body, err = decompress(body, resp.Header.Get("Content-Encoding"), maxSize); err != nil {
+		return nil, err
+	}
+	if result.Feed, err = Decode(body); err != nil {
+		return nil, err
+	}
+	result.Changed = true
+	return result, nil
+}
+
+//Reads r to the end, failing with ErrFeedTooLarge past maxSize bytes
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrFeedTooLarge
+	}
+	return data, nil
+}
+
+//Undoes a gzip or deflate Content-Encoding. Deflate is tried as zlib first, then as a raw stream since many servers get it wrong.
+//Output is limited to maxSize bytes so a small compressed response can't expand without bound.
+func decompress(body []byte, encoding string, maxSize int64) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return readLimited(zr, maxSize)
+	case "deflate":
+		if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+			defer zr.Close()
+			inflated, err := readLimited(zr, maxSize)
+			if err == nil || errors.Is(err, ErrFeedTooLarge) {
+				return inflated, err
+			}
+		}
+		fr := flate.NewReader(bytes.NewReader(body))
+		defer fr.Close()
+		return readLimited(fr, maxSize)
+	}
+	return body, nil
+}
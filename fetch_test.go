@@ -0,0 +1,246 @@
+package easyrss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+//Serves encodePlainFeed with caching validators, answering conditional requests with 304
+func validatorServer(t *testing.T, etag, lastModified string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if etag != "" && r.Header.Get("If-None-Match") == etag || lastModified != "" && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		if lastModified != "" {
+			w.Header().Set("Last-Modified", lastModified)
+		}
+		io.WriteString(w, encodePlainFeed)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchConditional(t *testing.T) {
+	tests := []struct {
+		name         string
+		etag         string
+		lastModified string
+	}{
+		{"etag", `"v1"`, ""},
+		{"last-modified", "", "Mon, 02 Jan 2006 15:04:05 GMT"},
+		{"both", `W/"v2"`, "Tue, 03 Jan 2006 15:04:05 GMT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := validatorServer(t, tt.etag, tt.lastModified)
+			f := &Fetcher{}
+			first, err := f.Fetch(context.Background(), srv.URL, Validators{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Validators{ETag: tt.etag, LastModified: tt.lastModified}
+			if !first.Changed || first.Feed == nil || first.Validators != want || first.StatusCode != http.StatusOK {
+				t.Fatalf("first fetch = %+v", first)
+			}
+			second, err := f.Fetch(context.Background(), srv.URL, first.Validators)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if second.Changed || second.Feed != nil || second.StatusCode != http.StatusNotModified {
+				t.Errorf("conditional fetch = %+v", second)
+			}
+			if second.Validators != want { //The 304 carries no validators, the cached ones must be kept
+				t.Errorf("validators after 304 = %+v, want %+v", second.Validators, want)
+			}
+		})
+	}
+}
+
+//A 304 may update one validator and leave out the other, which must be kept from the cache
+func TestFetchNotModifiedPartialValidators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+	cached := Validators{ETag: `"v1"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}
+	result, err := (&Fetcher{}).Fetch(context.Background(), srv.URL, cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Validators{ETag: `"v2"`, LastModified: cached.LastModified}); result.Validators != want {
+		t.Errorf("validators after 304 = %+v, want %+v", result.Validators, want)
+	}
+}
+
+func TestFetchCompression(t *testing.T) {
+	compressors := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"x-gzip":  func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+		"": nil,
+	}
+	for name, compressor := range compressors {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+					t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+				}
+				if compressor == nil {
+					io.WriteString(w, atomFeed)
+					return
+				}
+				w.Header().Set("Content-Encoding", strings.TrimPrefix(name, "raw "))
+				zw := compressor(w)
+				io.WriteString(zw, atomFeed)
+				zw.Close()
+			}))
+			defer srv.Close()
+			result, err := (&Fetcher{}).Fetch(context.Background(), srv.URL, Validators{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if title, _ := result.Feed.Title(); !result.Feed.IsAtom() || title != "Example Feed" {
+				t.Errorf("decoded feed title = %q", title)
+			}
+		})
+	}
+}
+
+func TestFetchRedirects(t *testing.T) {
+	tests := []struct {
+		name      string
+		hops      []int //Status of each redirect before the feed is served
+		permanent int   //Number of leading hops that count as a permanent move
+	}{
+		{"none", nil, 0},
+		{"moved permanently", []int{http.StatusMovedPermanently}, 1},
+		{"permanent redirect", []int{http.StatusPermanentRedirect}, 1},
+		{"two permanent", []int{http.StatusMovedPermanently, http.StatusPermanentRedirect}, 2},
+		{"permanent then temporary", []int{http.StatusMovedPermanently, http.StatusFound}, 1},
+		{"temporary then permanent", []int{http.StatusFound, http.StatusMovedPermanently}, 0},
+		{"temporary", []int{http.StatusTemporaryRedirect}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			for hop, status := range tt.hops {
+				next := "/hop" + string(rune('1'+hop))
+				if hop == len(tt.hops)-1 {
+					next = "/feed"
+				}
+				status := status
+				mux.HandleFunc("/hop"+string(rune('0'+hop)), func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, next, status)
+				})
+			}
+			mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, encodePlainFeed) })
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+			start := srv.URL + "/hop0"
+			if len(tt.hops) == 0 {
+				start = srv.URL + "/feed"
+			}
+			result, err := (&Fetcher{}).Fetch(context.Background(), start, Validators{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.URL != srv.URL+"/feed" {
+				t.Errorf("URL = %q", result.URL)
+			}
+			want := ""
+			switch {
+			case tt.permanent == len(tt.hops) && tt.permanent > 0:
+				want = srv.URL + "/feed"
+			case tt.permanent > 0:
+				want = srv.URL + "/hop" + string(rune('0'+tt.permanent))
+			}
+			if result.PermanentURL != want {
+				t.Errorf("PermanentURL = %q, want %q", result.PermanentURL, want)
+			}
+		})
+	}
+}
+
+func TestFetchLimits(t *testing.T) {
+	big := bytes.Repeat([]byte(" "), 1<<20)
+	var bomb bytes.Buffer
+	zw := gzip.NewWriter(&bomb)
+	zw.Write(big)
+	zw.Close()
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"plain", "", big},
+		{"gzip bomb", "gzip", bomb.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Write(tt.body)
+			}))
+			defer srv.Close()
+			_, err := (&Fetcher{MaxSize: 64 << 10}).Fetch(context.Background(), srv.URL, Validators{})
+			if !errors.Is(err, ErrFeedTooLarge) {
+				t.Errorf("error = %v, want ErrFeedTooLarge", err)
+			}
+		})
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/agent":
+			if r.Header.Get("User-Agent") != "easyrss-test" {
+				http.Error(w, "no agent", http.StatusForbidden)
+				return
+			}
+			io.WriteString(w, encodePlainFeed)
+		default:
+			io.WriteString(w, "<html><body>not a feed</body></html>")
+		}
+	}))
+	defer srv.Close()
+	f := &Fetcher{UserAgent: "easyrss-test"}
+	var statusErr *HTTPStatusError
+	if _, err := f.Fetch(context.Background(), srv.URL+"/missing", Validators{}); !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("404 error = %v", err)
+	}
+	if _, err := f.Fetch(context.Background(), srv.URL+"/agent", Validators{}); err != nil {
+		t.Errorf("User-Agent not sent: %v", err)
+	}
+	if _, err := f.Fetch(context.Background(), srv.URL+"/html", Validators{}); err != ErrNotFeed {
+		t.Errorf("html error = %v, want ErrNotFeed", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Fetch(ctx, srv.URL+"/agent", Validators{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled error = %v", err)
+	}
+}
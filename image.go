@@ -1,19 +1,44 @@
 package easyrss
 
-import "errors"
+import (
+	"strconv"
+	"strings"
+)
 
 type Image struct {
-	title  string
-	url    string
-	link   string
-	width  int
-	height int
+	title       string
+	url         string
+	link        string
+	description string
+	width       int
+	height      int
+}
+
+//Sets image fields from the children of an RSS <image> element. Fields without a child element are left untouched.
+func setImageFields(n node, i *Image) {
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		tagContent := strings.TrimSpace(activeElem.Content())
+		switch activeElem.Name() {
+		case "title":
+			i.title = tagContent
+		case "url":
+			i.url = tagContent
+		case "link":
+			i.link = tagContent
+		case "description":
+			i.description = tagContent
+		case "width":
+			i.width, _ = strconv.Atoi(tagContent)
+		case "height":
+			i.height, _ = strconv.Atoi(tagContent)
+		}
+	}
 }
 
 //Returns image title, if available. If not, an empty string is returned along with an error.
 func (i *Image) Title() (string, error) {
 	if i.title == "" {
-		return "", errors.New("Image title field is not populated")
+		return "", notPopulated(ElementImage, "", "title")
 	}
 	return i.title, nil
 }
@@ -21,7 +46,7 @@ func (i *Image) Title() (string, error) {
 //Returns the url where the image is located, if available. If not, an empty string is returned along with an error.
 func (i *Image) URL() (string, error) {
 	if i.url == "" {
-		return "", errors.New("Image URL is not populated")
+		return "", notPopulated(ElementImage, "", "url")
 	}
 	return i.url, nil
 }
@@ -29,15 +54,23 @@ func (i *Image) URL() (string, error) {
 //Returns the url where the image links to, if available. If not, an empty string is returned along with an error.
 func (i *Image) Link() (string, error) {
 	if i.link == "" {
-		return "", errors.New("Image link is not populated")
+		return "", notPopulated(ElementImage, "", "link")
 	}
 	return i.link, nil
 }
 
+//Returns the image description, used as the link title attribute, if available. If not, an empty string is returned along with an error.
+func (i *Image) Description() (string, error) {
+	if i.description == "" {
+		return "", notPopulated(ElementImage, "", "description")
+	}
+	return i.description, nil
+}
+
 //Returns the url image width in pixels, if available. If not, a width of 0 is returned along with an error.
 func (i *Image) Width() (int, error) {
 	if i.width == 0 {
-		return 0, errors.New("Image width is not populated")
+		return 0, notPopulated(ElementImage, "", "width")
 	}
 	return i.width, nil
 }
@@ -45,7 +78,7 @@ func (i *Image) Width() (int, error) {
 //Returns the url image width in pixels, if available. If not, a width of 0 is returned along with an error.
 func (i *Image) Height() (int, error) {
 	if i.height == 0 {
-		return 0, errors.New("Image height is not populated")
+		return 0, notPopulated(ElementImage, "", "height")
 	}
 	return i.height, nil
 }
@@ -0,0 +1,89 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const itemFeed = `<rss version="2.0"><channel><title>T</title><link>http://x</link><description>D</description>
+<item><title>a</title><author>a@x (A)</author><category domain="http://tax">News</category><category>Go</category>
+<guid>http://x/a</guid><comments>http://x/a#c</comments><source url="http://o/feed">Other</source></item>
+<item><title>b</title><guid isPermaLink="FALSE">tag:b</guid></item>
+<item><title>c</title><link>http://x/c</link><guid isPermaLink="true">http://x/c-guid</guid></item></channel></rss>`
+
+func checkItems(t *testing.T, r *RSS) {
+	t.Helper()
+	items, err := r.Items()
+	if err != nil || len(items) != 3 {
+		t.Fatalf("items = %d, %v", len(items), err)
+	}
+	a, b, c := items[0], items[1], items[2]
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"author", func() (interface{}, error) { return a.Author() }, "a@x (A)"},
+		{"categories", func() (interface{}, error) { return a.Categories() }, []Category{{Domain: "http://tax", Value: "News"}, {Value: "Go"}}},
+		{"guid defaults to permalink", func() (interface{}, error) { return a.GUID() }, GUIDField{IsPermaLink: true, Content: "http://x/a"}},
+		{"link falls back to guid", func() (interface{}, error) { return a.Link() }, "http://x/a"},
+		{"comments", func() (interface{}, error) { return a.Comments() }, "http://x/a#c"},
+		{"source", func() (interface{}, error) {
+			s, err := a.Source()
+			return *s, err
+		}, Source{URL: "http://o/feed", Title: "Other"}},
+		{"guid not a permalink", func() (interface{}, error) { return b.GUID() }, GUIDField{Content: "tag:b"}},
+		{"link preferred over guid", func() (interface{}, error) { return c.Link() }, "http://x/c"},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+	if link, err := b.Link(); !errors.Is(err, ErrFieldNotPopulated) {
+		t.Errorf("link of an item with a non-permalink guid = %q, %v", link, err)
+	}
+	missing := map[string]func() error{
+		"author":     func() error { _, err := b.Author(); return err },
+		"categories": func() error { _, err := b.Categories(); return err },
+		"comments":   func() error { _, err := b.Comments(); return err },
+		"source":     func() error { _, err := b.Source(); return err },
+	}
+	for name, get := range missing {
+		var fieldErr *FieldError
+		if err := get(); !errors.As(err, &fieldErr) || fieldErr.Element != ElementItem {
+			t.Errorf("missing %s error = %v", name, err)
+		}
+	}
+}
+
+func TestItemElements(t *testing.T) {
+	checkAllPaths(t, itemFeed, checkItems)
+}
+
+//Only an explicit "false" turns a guid into a non-permalink, and a source without a url still keeps its title
+func TestItemElementsMalformed(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>T</title><link>http://x</link><description>D</description>
+<item><title>a</title><guid isPermaLink="maybe">  http://x/a  </guid><source>Nameless</source></item>
+<item><title>b</title><guid isPermaLink=" false ">tag:b</guid></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, err := r.Items()
+		if err != nil || len(items) != 2 {
+			t.Fatalf("items = %d, %v", len(items), err)
+		}
+		if guid, err := items[0].GUID(); err != nil || guid != (GUIDField{IsPermaLink: true, Content: "http://x/a"}) {
+			t.Errorf("guid = %+v, %v", guid, err)
+		}
+		if source, err := items[0].Source(); err != nil || *source != (Source{Title: "Nameless"}) {
+			t.Errorf("source = %+v, %v", source, err)
+		}
+		if guid, err := items[1].GUID(); err != nil || guid.IsPermaLink {
+			t.Errorf("guid = %+v, %v", guid, err)
+		}
+		if link, err := items[1].Link(); !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("link = %q, %v", link, err)
+		}
+	})
+}
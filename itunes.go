@@ -1,25 +1,45 @@
 package easyrss
 
 import (
-	"errors"
-	"github.com/moovweb/gokogiri/xml"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type ItunesMeta struct {
-	author   string
-	subtitle string
-	summary  string
-	image    Image
-	explicit string
-	duration time.Duration
-	keywords string
+	author      string
+	subtitle    string
+	summary     string
+	image       Image
+	explicit    string
+	duration    time.Duration
+	keywords    []string
+	categories  []ItunesCategory //Channel only
+	owner       *ItunesOwner     //Channel only
+	podcastType string           //Channel only, episodic or serial
+	complete    bool             //Channel only
+	newFeedURL  string           //Channel only
+	title       string           //Item only
+	episode     int              //Item only
+	season      int              //Item only
+	episodeType string           //Item only, full, trailer or bonus
+	block       bool
+}
+
+//An Itunes podcast category. Apple allows one level of subcategories.
+type ItunesCategory struct {
+	Text          string
+	Subcategories []ItunesCategory
+}
+
+//Contact details of the podcast owner. Not shown publicly by Apple.
+type ItunesOwner struct {
+	Name  string
+	Email string
 }
 
 //Sets Appropriate Field Given Itunes Node
-func setItunesMetaField(n xml.Node, i *ItunesMeta) {
+func setItunesMetaField(n node, i *ItunesMeta) {
 	tag := n.Name()
 	tagContent := n.Content()
 	switch tag {
@@ -32,53 +52,98 @@ func setItunesMetaField(n xml.Node, i *ItunesMeta) {
 	case "explicit":
 		i.explicit = tagContent
 	case "keywords":
-		i.keywords = tagContent
+		i.keywords = splitKeywords(tagContent)
 	case "duration":
-		splitDur := strings.Split(tagContent, ":")
-		if len(splitDur) != 3 && len(splitDur) != 2 { //Not H:M:S/M:S format, so probably just a seconds integer
-			duration, err := time.ParseDuration(tagContent + "s")
-			if err != nil {
-				return
-			}
+		if duration, ok := parseItunesDuration(tagContent); ok {
 			i.duration = duration
-			return
 		}
-
-		if len(splitDur) == 3 {
-			hours, e := strconv.ParseInt(splitDur[0], 10, 8)
-			if e != nil {
-				return
-			}
-			minutes, e := strconv.ParseInt(splitDur[1], 10, 8)
-			if e != nil {
-				return
-			}
-			seconds, e := strconv.ParseInt(splitDur[2], 10, 8)
-			if e != nil {
-				return
-			}
-			i.duration = time.Duration(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
-		} else {
-			minutes, e := strconv.ParseInt(splitDur[0], 10, 8)
-			if e != nil {
-				return
-			}
-			seconds, e := strconv.ParseInt(splitDur[1], 10, 8)
-			if e != nil {
-				return
-			}
-			i.duration = time.Duration(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
-		}
-
 	case "image":
-		if urlNode := n.Attribute("href"); urlNode != nil {
-			i.image.url = urlNode.Value()
+		if urlNode := n.Attr("href"); urlNode != "" {
+			i.image.url = urlNode
 		}
+	case "category":
+		i.categories = append(i.categories, parseItunesCategory(n))
+	case "owner":
+		owner := ItunesOwner{}
+		for ownerElem := n.FirstChild(); ownerElem != nil; ownerElem = ownerElem.NextSibling() {
+			switch ownerElem.Name() {
+			case "name":
+				owner.Name = strings.TrimSpace(ownerElem.Content())
+			case "email":
+				owner.Email = strings.TrimSpace(ownerElem.Content())
+			}
+		}
+		i.owner = &owner
+	case "type":
+		i.podcastType = strings.ToLower(strings.TrimSpace(tagContent))
+	case "title":
+		i.title = tagContent
+	case "episode":
+		i.episode, _ = strconv.Atoi(strings.TrimSpace(tagContent))
+	case "season":
+		i.season, _ = strconv.Atoi(strings.TrimSpace(tagContent))
+	case "episodeType":
+		i.episodeType = strings.ToLower(strings.TrimSpace(tagContent))
+	case "block":
+		i.block = isItunesYes(tagContent)
+	case "complete":
+		i.complete = isItunesYes(tagContent)
+	case "new-feed-url":
+		i.newFeedURL = strings.TrimSpace(tagContent)
 	default:
 		return
 	}
 }
 
+func parseItunesCategory(n node) ItunesCategory {
+	category := ItunesCategory{Text: n.Attr("text")}
+	for sub := n.FirstChild(); sub != nil; sub = sub.NextSibling() {
+		if sub.Name() == "category" && sub.Namespace() == itunesNS {
+			category.Subcategories = append(category.Subcategories, parseItunesCategory(sub))
+		}
+	}
+	return category
+}
+
+//Itunes flags such as block and complete are only set by "Yes". Anything else, including "no", leaves them unset.
+func isItunesYes(tagContent string) bool {
+	return strings.EqualFold(strings.TrimSpace(tagContent), "yes")
+}
+
+//Splits a comma separated keyword list, dropping empty entries
+func splitKeywords(tagContent string) []string {
+	var keywords []string
+	for _, keyword := range strings.Split(tagContent, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+//Parses an Itunes duration in H:M:S, M:S or plain seconds format. Returns false for negative or malformed durations.
+func parseItunesDuration(tagContent string) (time.Duration, bool) {
+	tagContent = strings.TrimSpace(tagContent)
+	splitDur := strings.Split(tagContent, ":")
+	if len(splitDur) != 3 && len(splitDur) != 2 { //Not H:M:S/M:S format, so probably just a seconds integer
+		duration, err := time.ParseDuration(tagContent + "s")
+		if err != nil || duration < 0 {
+			return 0, false
+		}
+		return duration, true
+	}
+
+	var duration time.Duration
+	for _, part := range splitDur {
+		value, err := strconv.ParseUint(part, 10, 32) //Unsigned, so "-5:00" is rejected
+		if err != nil {
+			return 0, false
+		}
+		duration = duration*60 + time.Duration(value)*time.Second
+	}
+	return duration, true
+}
+
 //Whether or not this feed implements ItunesRSS Extensions
 func (r *RSS) IsItunes() bool {
 	return r.channel.isItunes
@@ -87,10 +152,10 @@ func (r *RSS) IsItunes() bool {
 //Returns the Itunes "author" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "author" field, will return an empty string and an error
 func (r *RSS) ItunesAuthor() (string, error) {
 	if !r.channel.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
 	if r.channel.itunes.author == "" {
-		return "", errors.New("Itunes author field not populated")
+		return "", notPopulated(ElementChannel, NamespaceItunes, "author")
 	}
 	return r.channel.itunes.author, nil
 }
@@ -98,10 +163,10 @@ func (r *RSS) ItunesAuthor() (string, error) {
 //Returns the Itunes "author" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "subtitle" field, will return an empty string and an error
 func (r *RSS) ItunesSubtitle() (string, error) {
 	if !r.channel.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
 	if r.channel.itunes.subtitle == "" {
-		return "", errors.New("Itunes subtitle field not populated")
+		return "", notPopulated(ElementChannel, NamespaceItunes, "subtitle")
 	}
 	return r.channel.itunes.subtitle, nil
 }
@@ -109,21 +174,21 @@ func (r *RSS) ItunesSubtitle() (string, error) {
 //Returns the Itunes "summary" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "summary" field, will return an empty string and an error
 func (r *RSS) ItunesSummary() (string, error) {
 	if !r.channel.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
-	if r.channel.itunes.subtitle == "" {
-		return "", errors.New("Itunes summary field not populated")
+	if r.channel.itunes.summary == "" {
+		return "", notPopulated(ElementChannel, NamespaceItunes, "summary")
 	}
-	return r.channel.itunes.subtitle, nil
+	return r.channel.itunes.summary, nil
 }
 
 //Returns the Itunes "image" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "image" field, will return nil and an error.
 func (r *RSS) ItunesImage() (*Image, error) {
 	if !r.channel.isItunes {
-		return nil, errors.New("Not an Itunes RSS Feed")
+		return nil, ErrNotItunes
 	}
 	if r.channel.itunes.image.url == "" {
-		return nil, errors.New("Itunes image fields not populated")
+		return nil, notPopulated(ElementChannel, NamespaceItunes, "image")
 	}
 	return &r.channel.itunes.image, nil
 }
@@ -131,10 +196,10 @@ func (r *RSS) ItunesImage() (*Image, error) {
 //Returns the Itunes "explicit" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "explicit" field, will return an empty string and an error func (r *RSS) ItunesExplicit() (string, error) {
 func (r *RSS) ItunesExplicit() (string, error) {
 	if !r.channel.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
 	if r.channel.itunes.explicit == "" {
-		return "", errors.New("Itunes explicit field not populated")
+		return "", notPopulated(ElementChannel, NamespaceItunes, "explicit")
 	}
 	return r.channel.itunes.explicit, nil
 }
@@ -142,10 +207,10 @@ func (r *RSS) ItunesExplicit() (string, error) {
 //Returns the Itunes "author" field for the item. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "author" field, will return an empty string and an error
 func (i *Item) ItunesAuthor() (string, error) {
 	if !i.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
 	if i.itunes.author == "" {
-		return "", errors.New("Itunes author field not populated")
+		return "", notPopulated(ElementItem, NamespaceItunes, "author")
 	}
 	return i.itunes.author, nil
 }
@@ -153,10 +218,10 @@ func (i *Item) ItunesAuthor() (string, error) {
 //Returns the Itunes "author" field for the item. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "subtitle" field, will return an empty string and an error
 func (i *Item) ItunesSubtitle() (string, error) {
 	if !i.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
 	if i.itunes.subtitle == "" {
-		return "", errors.New("Itunes subtitle field not populated")
+		return "", notPopulated(ElementItem, NamespaceItunes, "subtitle")
 	}
 	return i.itunes.subtitle, nil
 }
@@ -164,21 +229,21 @@ func (i *Item) ItunesSubtitle() (string, error) {
 //Returns the Itunes "summary" field for the item. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "summary" field, will return an empty string and an error
 func (i *Item) ItunesSummary() (string, error) {
 	if !i.isItunes {
-		return "", errors.New("Not an Itunes RSS Feed")
+		return "", ErrNotItunes
 	}
-	if i.itunes.subtitle == "" {
-		return "", errors.New("Itunes summary field not populated")
+	if i.itunes.summary == "" {
+		return "", notPopulated(ElementItem, NamespaceItunes, "summary")
 	}
-	return i.itunes.subtitle, nil
+	return i.itunes.summary, nil
 }
 
 //Returns Itunes episode duration. If this information wasn't available or the item doesn't contain Itunes Extensions then we return nil and an error.
 func (i Item) ItunesDuration() (*time.Duration, error) {
 	if !i.isItunes {
-		return nil, errors.New("Not an Itunes RSS Feed")
+		return nil, ErrNotItunes
 	}
 	if int(i.itunes.duration) == 0 {
-		return nil, errors.New("Itunes duration field missing")
+		return nil, notPopulated(ElementItem, NamespaceItunes, "duration")
 	}
 	return &i.itunes.duration, nil
 }
@@ -186,10 +251,155 @@ func (i Item) ItunesDuration() (*time.Duration, error) {
 //Returns the Itunes "image" field for the item. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "image" field, will return nil and an error.
 func (i *Item) ItunesImage() (*Image, error) {
 	if !i.isItunes {
-		return nil, errors.New("Not an Itunes RSS Feed")
+		return nil, ErrNotItunes
 	}
 	if i.itunes.image.url == "" {
-		return nil, errors.New("Itunes image fields not populated")
+		return nil, notPopulated(ElementItem, NamespaceItunes, "image")
 	}
 	return &i.itunes.image, nil
 }
+
+//Returns the Itunes "keywords" field for the channel, split on commas. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "keywords" field, will return nil and an error.
+func (r *RSS) ItunesKeywords() ([]string, error) {
+	if !r.channel.isItunes {
+		return nil, ErrNotItunes
+	}
+	if len(r.channel.itunes.keywords) == 0 {
+		return nil, notPopulated(ElementChannel, NamespaceItunes, "keywords")
+	}
+	return r.channel.itunes.keywords, nil
+}
+
+//Returns the Itunes categories for the channel. If the channel doesn't contain ITunes Extensions or has no Itunes "category" fields, will return nil and an error.
+func (r *RSS) ItunesCategories() ([]ItunesCategory, error) {
+	if !r.channel.isItunes {
+		return nil, ErrNotItunes
+	}
+	if len(r.channel.itunes.categories) == 0 {
+		return nil, notPopulated(ElementChannel, NamespaceItunes, "category")
+	}
+	return r.channel.itunes.categories, nil
+}
+
+//Returns the Itunes "owner" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the Itunes "owner" field, will return nil and an error.
+func (r *RSS) ItunesOwner() (*ItunesOwner, error) {
+	if !r.channel.isItunes {
+		return nil, ErrNotItunes
+	}
+	if r.channel.itunes.owner == nil {
+		return nil, notPopulated(ElementChannel, NamespaceItunes, "owner")
+	}
+	return r.channel.itunes.owner, nil
+}
+
+//Returns the Itunes "type" field for the channel, either "episodic" or "serial". If the channel doesn't contain ITunes Extensions or hasn't populated the Itunes "type" field, will return an empty string and an error.
+func (r *RSS) ItunesType() (string, error) {
+	if !r.channel.isItunes {
+		return "", ErrNotItunes
+	}
+	if r.channel.itunes.podcastType == "" {
+		return "", notPopulated(ElementChannel, NamespaceItunes, "type")
+	}
+	return r.channel.itunes.podcastType, nil
+}
+
+//Returns the Itunes "new-feed-url" field, the URL the podcast has moved to. If the channel doesn't contain ITunes Extensions or hasn't populated the field, will return an empty string and an error.
+func (r *RSS) ItunesNewFeedURL() (string, error) {
+	if !r.channel.isItunes {
+		return "", ErrNotItunes
+	}
+	if r.channel.itunes.newFeedURL == "" {
+		return "", notPopulated(ElementChannel, NamespaceItunes, "new-feed-url")
+	}
+	return r.channel.itunes.newFeedURL, nil
+}
+
+//Whether the podcast is hidden from Apple Podcasts. Only returns an error if the channel doesn't contain ITunes Extensions.
+func (r *RSS) ItunesBlock() (bool, error) {
+	if !r.channel.isItunes {
+		return false, ErrNotItunes
+	}
+	return r.channel.itunes.block, nil
+}
+
+//Whether the podcast will never publish another episode. Only returns an error if the channel doesn't contain ITunes Extensions.
+func (r *RSS) ItunesComplete() (bool, error) {
+	if !r.channel.isItunes {
+		return false, ErrNotItunes
+	}
+	return r.channel.itunes.complete, nil
+}
+
+//Returns the Itunes "explicit" field for the item. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "explicit" field, will return an empty string and an error
+func (i *Item) ItunesExplicit() (string, error) {
+	if !i.isItunes {
+		return "", ErrNotItunes
+	}
+	if i.itunes.explicit == "" {
+		return "", notPopulated(ElementItem, NamespaceItunes, "explicit")
+	}
+	return i.itunes.explicit, nil
+}
+
+//Returns the Itunes "keywords" field for the item, split on commas. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "keywords" field, will return nil and an error.
+func (i *Item) ItunesKeywords() ([]string, error) {
+	if !i.isItunes {
+		return nil, ErrNotItunes
+	}
+	if len(i.itunes.keywords) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceItunes, "keywords")
+	}
+	return i.itunes.keywords, nil
+}
+
+//Returns the Itunes "title" field for the item, the episode title without numbering. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "title" field, will return an empty string and an error.
+func (i *Item) ItunesTitle() (string, error) {
+	if !i.isItunes {
+		return "", ErrNotItunes
+	}
+	if i.itunes.title == "" {
+		return "", notPopulated(ElementItem, NamespaceItunes, "title")
+	}
+	return i.itunes.title, nil
+}
+
+//Returns the Itunes episode number. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "episode" field, will return 0 and an error.
+func (i *Item) ItunesEpisode() (int, error) {
+	if !i.isItunes {
+		return 0, ErrNotItunes
+	}
+	if i.itunes.episode == 0 {
+		return 0, notPopulated(ElementItem, NamespaceItunes, "episode")
+	}
+	return i.itunes.episode, nil
+}
+
+//Returns the Itunes season number. If the item doesn't contain ITunes Extensions or hasn't populated the Itunes "season" field, will return 0 and an error.
+func (i *Item) ItunesSeason() (int, error) {
+	if !i.isItunes {
+		return 0, ErrNotItunes
+	}
+	if i.itunes.season == 0 {
+		return 0, notPopulated(ElementItem, NamespaceItunes, "season")
+	}
+	return i.itunes.season, nil
+}
+
+//Returns the Itunes "episodeType" field, one of "full", "trailer" or "bonus". If the item doesn't contain ITunes Extensions or hasn't populated the field, will return an empty string and an error.
+func (i *Item) ItunesEpisodeType() (string, error) {
+	if !i.isItunes {
+		return "", ErrNotItunes
+	}
+	if i.itunes.episodeType == "" {
+		return "", notPopulated(ElementItem, NamespaceItunes, "episodeType")
+	}
+	return i.itunes.episodeType, nil
+}
+
+//Whether the episode is hidden from Apple Podcasts. Only returns an error if the item doesn't contain ITunes Extensions.
+func (i *Item) ItunesBlock() (bool, error) {
+	if !i.isItunes {
+		return false, ErrNotItunes
+	}
+	return i.itunes.block, nil
+}
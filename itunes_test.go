@@ -0,0 +1,125 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const itunesFeed = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>P</title><link>http://p</link><description>D</description>
+<itunes:summary>Sum</itunes:summary><itunes:subtitle>Sub</itunes:subtitle><itunes:keywords>a, b,,c</itunes:keywords>
+<itunes:category text="Technology"><itunes:category text="Podcasting"/></itunes:category><itunes:category text="News"/>
+<itunes:owner><itunes:name>O</itunes:name><itunes:email>o@p</itunes:email></itunes:owner><itunes:type>Serial</itunes:type>
+<itunes:block>Yes</itunes:block><itunes:complete>no</itunes:complete><itunes:new-feed-url>http://new</itunes:new-feed-url>
+<item><title>1</title><itunes:title>Ep</itunes:title><itunes:episode>3</itunes:episode><itunes:season>2</itunes:season><itunes:episodeType>trailer</itunes:episodeType><itunes:duration>150:00</itunes:duration><itunes:summary>ISum</itunes:summary></item>
+<item><title>2</title><itunes:duration>-5:00</itunes:duration></item>
+</channel></rss>`
+
+func checkItunes(t *testing.T, r *RSS) {
+	t.Helper()
+	items, err := r.Items()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("items = %d, %v", len(items), err)
+	}
+	item := items[0]
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"summary", func() (interface{}, error) { return r.ItunesSummary() }, "Sum"},
+		{"subtitle", func() (interface{}, error) { return r.ItunesSubtitle() }, "Sub"},
+		{"keywords", func() (interface{}, error) { return r.ItunesKeywords() }, []string{"a", "b", "c"}},
+		{"categories", func() (interface{}, error) { return r.ItunesCategories() }, []ItunesCategory{
+			{Text: "Technology", Subcategories: []ItunesCategory{{Text: "Podcasting"}}}, {Text: "News"}}},
+		{"owner", func() (interface{}, error) {
+			o, err := r.ItunesOwner()
+			return *o, err
+		}, ItunesOwner{Name: "O", Email: "o@p"}},
+		{"type", func() (interface{}, error) { return r.ItunesType() }, "serial"},
+		{"block", func() (interface{}, error) { return r.ItunesBlock() }, true},
+		{"complete", func() (interface{}, error) { return r.ItunesComplete() }, false},
+		{"new-feed-url", func() (interface{}, error) { return r.ItunesNewFeedURL() }, "http://new"},
+		{"item title", func() (interface{}, error) { return item.ItunesTitle() }, "Ep"},
+		{"item episode", func() (interface{}, error) { return item.ItunesEpisode() }, 3},
+		{"item season", func() (interface{}, error) { return item.ItunesSeason() }, 2},
+		{"item episodeType", func() (interface{}, error) { return item.ItunesEpisodeType() }, "trailer"},
+		{"item summary", func() (interface{}, error) { return item.ItunesSummary() }, "ISum"},
+		{"item duration", func() (interface{}, error) {
+			d, err := item.ItunesDuration()
+			return *d, err
+		}, 150 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+	if d, err := items[1].ItunesDuration(); d != nil || err == nil { //A re-encoded item with no Itunes fields left reports ErrNotItunes
+		t.Errorf("negative duration = %v, %v", d, err)
+	}
+}
+
+func TestItunesElements(t *testing.T) {
+	checkAllPaths(t, itunesFeed, checkItunes)
+}
+
+//Numbers and images that can't be read leave their field unpopulated, and items without Itunes fields report ErrNotItunes
+func TestItunesElementsMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>P</title><link>http://p</link><description>D</description>
+<item><title>1</title><itunes:title>Ep</itunes:title><itunes:episode>three</itunes:episode><itunes:season>-</itunes:season><itunes:image/>
+<itunes:block>maybe</itunes:block></item><item><title>2</title></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, err := r.Items()
+		if err != nil || len(items) != 2 {
+			t.Fatalf("items = %d, %v", len(items), err)
+		}
+		item := items[0]
+		missing := map[string]func() error{
+			"episode": func() error { _, err := item.ItunesEpisode(); return err },
+			"season":  func() error { _, err := item.ItunesSeason(); return err },
+			"image":   func() error { _, err := item.ItunesImage(); return err },
+		}
+		for name, get := range missing {
+			var fieldErr *FieldError
+			if err := get(); !errors.As(err, &fieldErr) || fieldErr.Namespace != NamespaceItunes || fieldErr.Field != name {
+				t.Errorf("%s error = %v", name, err)
+			}
+		}
+		if block, err := item.ItunesBlock(); err != nil || block {
+			t.Errorf("block = %v, %v", block, err)
+		}
+		if _, err := items[1].ItunesTitle(); !errors.Is(err, ErrNotItunes) {
+			t.Errorf("plain item error = %v, want ErrNotItunes", err)
+		}
+	})
+}
+
+func TestParseItunesDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"150:00", 150 * time.Minute, true},
+		{"05:07", 5*time.Minute + 7*time.Second, true},
+		{"3600", time.Hour, true},
+		{" 90 ", 90 * time.Second, true},
+		{"1.5", 1500 * time.Millisecond, true},
+		{"-5:00", 0, false},
+		{"1:-5:00", 0, false},
+		{"-30", 0, false},
+		{"+5:00", 0, false},
+		{"1:2:3:4", 0, false},
+		{"ab:cd", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := parseItunesDuration(tt.in); ok != tt.ok || got != tt.want {
+			t.Errorf("parseItunesDuration(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package easyrss
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	Authors     []jsonAuthor   `json:"authors,omitempty"`
+	Author      *jsonAuthor    `json:"author,omitempty"` //JSON Feed 1.0, superseded by authors
+	Itunes      *jsonItunes    `json:"_itunes,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+//Size and duration are JSON numbers, which some feeds write with a fractional part
+type jsonAttachment struct {
+	URL      string  `json:"url"`
+	MimeType string  `json:"mime_type"`
+	Size     float64 `json:"size_in_bytes,omitempty"`
+	Duration float64 `json:"duration_in_seconds,omitempty"`
+}
+
+//Itunes fields carried in a "_itunes" extension object
+type jsonItunes struct {
+	Author      string      `json:"author,omitempty"`
+	Subtitle    string      `json:"subtitle,omitempty"`
+	Summary     string      `json:"summary,omitempty"`
+	Explicit    string      `json:"explicit,omitempty"`
+	Keywords    string      `json:"keywords,omitempty"`
+	Image       string      `json:"image,omitempty"`
+	Duration    interface{} `json:"duration,omitempty"` //Seconds, or an H:M:S string
+	Type        string      `json:"type,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Episode     int         `json:"episode,omitempty"`
+	Season      int         `json:"season,omitempty"`
+	EpisodeType string      `json:"episode_type,omitempty"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor     `json:"authors,omitempty"`
+	Author        *jsonAuthor      `json:"author,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Attachments   []jsonAttachment `json:"attachments,omitempty"`
+	Itunes        *jsonItunes      `json:"_itunes,omitempty"`
+}
+
+//Whether data looks like a JSON document rather than XML
+func isJSON(data []byte) bool {
+	trimmed := trimJSON(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+//Drops leading whitespace and the byte order mark, which encoding/json rejects
+func trimJSON(data []byte) []byte {
+	return bytes.TrimLeft(data, " \t\r\n\ufeff")
+}
+
+//Pass in a byte slice containing a JSON Feed (version 1.0 or 1.1), get an *RSS back. Decode calls this automatically for JSON input.
+func DecodeJSON(data []byte) (*RSS, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(trimJSON(data), &feed); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, ErrNotFeed
+	}
+	rssObj := RSS{}
+	c := &rssObj.channel
+	c.isJSONFeed = true
+	c.title = feed.Title
+	c.link = feed.HomePageURL
+	c.description = feed.Description
+	c.language = feed.Language
+	c.atomAuthor = jsonAuthorName(feed.Authors, feed.Author)
+	if feed.Itunes != nil {
+		c.isItunes = true
+		setItunesFromJSON(feed.Itunes, &c.itunes)
+	}
+	c.items = make([]Item, len(feed.Items))
+	for itemID, jsonItem := range feed.Items {
+		item := &c.items[itemID]
+		item.guid = GUIDField{IsPermaLink: jsonItem.ID == jsonItem.URL, Content: jsonItem.ID}
+		item.link = jsonItem.URL
+		item.title = jsonItem.Title
+		item.content = jsonItem.ContentHTML
+		if item.content == "" {
+			item.content = jsonItem.ContentText
+			item.isPlainText = item.content != ""
+		}
+		item.description = jsonItem.Summary
+		if item.description == "" {
+			item.description = item.content
+		}
+		if jsonItem.DatePublished != "" {
+			item.date = parseDate(jsonItem.DatePublished)
+		}
+		if item.date == nil && jsonItem.DateModified != "" {
+			item.date = parseDate(jsonItem.DateModified)
+		}
+		item.author = jsonAuthorName(jsonItem.Authors, jsonItem.Author)
+		item.inheritAuthor(c.atomAuthor)
+		for _, tag := range jsonItem.Tags {
+			item.categories = append(item.categories, Category{Value: tag})
+		}
+		if len(jsonItem.Attachments) > 0 { //Items only hold a single enclosure, so the first attachment wins
+			attachment := jsonItem.Attachments[0]
+			item.hasEnclosure = true
+			item.enclosure = RSSEnclosure{url: attachment.URL, mediaType: attachment.MimeType}
+			if attachment.Size > 0 {
+				item.enclosure.size = uint64(attachment.Size)
+			}
+			if attachment.Duration > 0 {
+				item.isItunes = true
+				item.itunes.duration = time.Duration(attachment.Duration * float64(time.Second))
+			}
+		}
+		if jsonItem.Itunes != nil {
+			item.isItunes = true
+			setItunesFromJSON(jsonItem.Itunes, &item.itunes)
+		}
+	}
+	return &rssObj, nil
+}
+
+//Returns the name of the first author, falling back to the JSON Feed 1.0 author object. Items without an author inherit the one of
+//the feed, as they do in Atom.
+func jsonAuthorName(authors []jsonAuthor, author *jsonAuthor) string {
+	if len(authors) > 0 {
+		return authors[0].Name
+	}
+	if author != nil {
+		return author.Name
+	}
+	return ""
+}
+
+func setItunesFromJSON(j *jsonItunes, i *ItunesMeta) {
+	i.author = j.Author
+	i.subtitle = j.Subtitle
+	i.summary = j.Summary
+	i.explicit = j.Explicit
+	i.keywords = splitKeywords(j.Keywords)
+	i.image.url = j.Image
+	i.podcastType = j.Type
+	i.title = j.Title
+	i.episode = j.Episode
+	i.season = j.Season
+	i.episodeType = j.EpisodeType
+	switch duration := j.Duration.(type) {
+	case float64:
+		if duration > 0 {
+			i.duration = time.Duration(duration * float64(time.Second))
+		}
+	case string:
+		if parsed, ok := parseItunesDuration(duration); ok {
+			i.duration = parsed
+		}
+	}
+}
+
+func itunesToJSON(i *ItunesMeta) *jsonItunes {
+	j := &jsonItunes{
+		Author:      i.author,
+		Subtitle:    i.subtitle,
+		Summary:     i.summary,
+		Explicit:    i.explicit,
+		Keywords:    strings.Join(i.keywords, ","),
+		Image:       i.image.url,
+		Type:        i.podcastType,
+		Title:       i.title,
+		Episode:     i.episode,
+		Season:      i.season,
+		EpisodeType: i.episodeType,
+	}
+	if i.duration > 0 {
+		j.Duration = int64(i.duration / time.Second)
+	}
+	return j
+}
+
+//Pass in an *RSS, get a JSON Feed 1.1 document back. Itunes metadata is carried in "_itunes" extension objects. Items need an id in
+//JSON Feed: the guid is used, then the link, then a hash of the item text.
+func EncodeJSON(r *RSS) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeJSONTo(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//Same as EncodeJSON, but writes the document to w.
+func EncodeJSONTo(w io.Writer, r *RSS) error {
+	if r == nil {
+		return ErrNilFeed
+	}
+	c := &r.channel
+	feed := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       c.title,
+		HomePageURL: c.link,
+		Description: c.description,
+		Language:    c.language,
+		Items:       make([]jsonFeedItem, len(c.items)),
+	}
+	if c.isItunes {
+		feed.Itunes = itunesToJSON(&c.itunes)
+	}
+	switch {
+	case c.isItunes && c.itunes.author != "":
+		feed.Authors = []jsonAuthor{{Name: c.itunes.author}}
+	case c.atomAuthor != "":
+		feed.Authors = []jsonAuthor{{Name: c.atomAuthor}}
+	}
+	for itemID := range c.items {
+		item := &c.items[itemID]
+		jsonItem := &feed.Items[itemID]
+		jsonItem.ID = item.guid.Content
+		if jsonItem.ID == "" { //id is required, the link is the next best identifier
+			jsonItem.ID = item.link
+		}
+		if jsonItem.ID == "" {
+			id, err := jsonItemID(item)
+			if err != nil {
+				return fmt.Errorf("Item %d has no identifier: %w", itemID, err)
+			}
+			jsonItem.ID = id
+		}
+		jsonItem.URL = item.link
+		jsonItem.Title = item.title
+		switch {
+		case item.content == "":
+			jsonItem.ContentHTML = item.description
+		case item.isPlainText:
+			jsonItem.ContentText = item.content
+		default:
+			jsonItem.ContentHTML = item.content
+		}
+		if item.content != "" && item.description != item.content {
+			jsonItem.Summary = item.description
+		}
+		if item.date != nil {
+			jsonItem.DatePublished = item.date.Format(time.RFC3339)
+		}
+		if item.author != "" {
+			jsonItem.Authors = []jsonAuthor{{Name: item.author}}
+		}
+		for _, category := range item.categories {
+			jsonItem.Tags = append(jsonItem.Tags, category.Value)
+		}
+		if item.hasEnclosure {
+			attachment := jsonAttachment{URL: item.enclosure.url, MimeType: item.enclosure.mediaType, Size: float64(item.enclosure.size)}
+			if item.isItunes {
+				attachment.Duration = item.itunes.duration.Seconds()
+			}
+			jsonItem.Attachments = []jsonAttachment{attachment}
+		}
+		if item.isItunes {
+			jsonItem.Itunes = itunesToJSON(&item.itunes)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(&feed)
+}
+
+//Derives an id for an item with neither guid nor link from its text, so the same item gets the same id every time it is encoded.
+//Returns a *FieldError if the item has no text either.
+func jsonItemID(item *Item) (string, error) {
+	if item.title == "" && item.description == "" && item.content == "" {
+		return "", notPopulated(ElementItem, "", "guid")
+	}
+	hash := sha1.New()
+	for _, field := range []string{item.title, item.description, item.content} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	if item.date != nil {
+		hash.Write([]byte(item.date.UTC().Format(time.RFC3339)))
+	}
+	return "urn:sha1:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+//Whether or not this feed was decoded from a JSON Feed document
+func (r *RSS) IsJSONFeed() bool {
+	return r.channel.isJSONFeed
+}
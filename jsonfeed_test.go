@@ -0,0 +1,226 @@
+package easyrss
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const jsonFeedDoc = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "JSON Feed",
+	"home_page_url": "https://example.org/",
+	"description": "About JSON",
+	"language": "en",
+	"_itunes": {"author": "Bob", "explicit": "no", "keywords": "a,b"},
+	"items": [
+		{"id": "1", "url": "https://example.org/1", "title": "HTML", "content_html": "<p>Hi</p>", "summary": "Short",
+		 "date_published": "2024-05-01T10:00:00Z", "authors": [{"name": "Alice"}], "tags": ["x", "y"],
+		 "attachments": [{"url": "https://example.org/1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 42, "duration_in_seconds": 90}]},
+		{"id": "https://example.org/2", "url": "https://example.org/2", "content_text": "Plain <text>",
+		 "date_modified": "2024-05-02T10:00:00Z", "author": {"name": "Carol"}}
+	]
+}`
+
+func TestDecodeJSON(t *testing.T) {
+	for name, doc := range map[string]string{"plain": jsonFeedDoc, "bom": "\ufeff" + jsonFeedDoc, "whitespace": "\n \ufeff" + jsonFeedDoc} {
+		t.Run(name, func(t *testing.T) {
+			r, err := Decode([]byte(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.IsJSONFeed() {
+				t.Error("IsJSONFeed() = false")
+			}
+			items, err := r.Items()
+			if err != nil || len(items) != 2 {
+				t.Fatal(items, err)
+			}
+			first, second := items[0], items[1]
+			tests := []struct {
+				name string
+				get  func() (string, error)
+				want string
+			}{
+				{"title", r.Title, "JSON Feed"},
+				{"home page", r.Link, "https://example.org/"},
+				{"language", r.Language, "en"},
+				{"itunes author", r.ItunesAuthor, "Bob"},
+				{"item title", first.Title, "HTML"},
+				{"content_html", first.Content, "<p>Hi</p>"},
+				{"summary", first.Description, "Short"},
+				{"authors", first.Author, "Alice"},
+				{"content_text", second.Content, "Plain <text>"},
+				{"description from content", second.Description, "Plain <text>"},
+				{"author", second.Author, "Carol"},
+			}
+			for _, tt := range tests {
+				if got, err := tt.get(); err != nil || got != tt.want {
+					t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+				}
+			}
+			if g, _ := first.GUID(); g.Content != "1" || g.IsPermaLink {
+				t.Errorf("id = %+v", g)
+			}
+			if g, _ := second.GUID(); !g.IsPermaLink {
+				t.Errorf("id equal to url should be a permalink: %+v", g)
+			}
+			if d, _ := second.Date(); !d.Equal(time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("date_modified = %v", d)
+			}
+			if first.EnclosureURL() != "https://example.org/1.mp3" || first.enclosure.size != 42 {
+				t.Errorf("attachment = %+v", first.enclosure)
+			}
+			if d, err := first.ItunesDuration(); err != nil || *d != 90*time.Second {
+				t.Errorf("duration = %v, %v", d, err)
+			}
+			if c, _ := first.Categories(); len(c) != 2 || c[1].Value != "y" {
+				t.Errorf("tags = %v", c)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	if _, err := DecodeJSON([]byte(`{"version": "1", "items": []}`)); err != ErrNotFeed {
+		t.Errorf("unknown version error = %v, want ErrNotFeed", err)
+	}
+	if _, err := DecodeJSON([]byte(`{"version": `)); err == nil {
+		t.Error("truncated document decoded")
+	}
+}
+
+//Numbers may have a fractional part, and items without authors get the ones of the feed
+func TestDecodeJSONFeedAuthorsAndNumbers(t *testing.T) {
+	const doc = `{"version": "https://jsonfeed.org/version/1.1", "title": "T", "authors": [{"name": "Dana"}], "items": [
+		{"id": "1", "attachments": [{"url": "https://example.org/1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 123.5, "duration_in_seconds": 90.5}]},
+		{"id": "2", "authors": [{"name": "Eve"}]}]}`
+	r, err := DecodeJSON([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := r.Items()
+	if author, _ := items[0].Author(); author != "Dana" {
+		t.Errorf("inherited author = %q", author)
+	}
+	if author, _ := items[1].Author(); author != "Eve" {
+		t.Errorf("item author = %q", author)
+	}
+	if items[0].enclosure.size != 123 {
+		t.Errorf("size = %d", items[0].enclosure.size)
+	}
+	if d, err := items[0].ItunesDuration(); err != nil || *d != 90500*time.Millisecond {
+		t.Errorf("duration = %v, %v", d, err)
+	}
+}
+
+func TestEncodeJSONRoundTrip(t *testing.T) {
+	r, err := Decode([]byte(jsonFeedDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := EncodeJSON(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed jsonFeed
+	if err := json.Unmarshal(out, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Version != jsonFeedVersion || len(feed.Items) != 2 {
+		t.Fatalf("encoded feed = %+v", feed)
+	}
+	if feed.Items[0].ContentHTML != "<p>Hi</p>" || feed.Items[0].Summary != "Short" {
+		t.Errorf("html item = %+v", feed.Items[0])
+	}
+	if feed.Items[1].ContentText != "Plain <text>" || feed.Items[1].ContentHTML != "" {
+		t.Errorf("text item = %+v", feed.Items[1])
+	}
+	again, err := DecodeJSON(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out2, err := EncodeJSON(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(out2) {
+		t.Errorf("round trip changed the feed\n%s\n%s", out, out2)
+	}
+}
+
+//The feed author survives the way through JSON Feed, whether it came from JSON Feed authors or an Atom author
+func TestEncodeJSONFeedAuthor(t *testing.T) {
+	docs := map[string]string{
+		"json feed": `{"version": "https://jsonfeed.org/version/1.1", "title": "T", "authors": [{"name": "Dana"}], "items": [{"id": "1"}]}`,
+		"atom": `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title><author><name>Dana</name></author>
+			<entry><id>1</id><title>E</title></entry></feed>`,
+	}
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			r, err := Decode([]byte(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := EncodeJSON(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var feed jsonFeed
+			if err := json.Unmarshal(out, &feed); err != nil {
+				t.Fatal(err)
+			}
+			if len(feed.Authors) != 1 || feed.Authors[0].Name != "Dana" {
+				t.Errorf("feed authors = %+v", feed.Authors)
+			}
+			again, err := DecodeJSON(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.channel.atomAuthor != "Dana" {
+				t.Errorf("decoded feed author = %q", again.channel.atomAuthor)
+			}
+		})
+	}
+}
+
+func TestEncodeJSONItemID(t *testing.T) {
+	tests := []struct {
+		name string
+		item *ItemBuilder
+		want string
+	}{
+		{"guid", NewItem("T", "http://x/1", "").WithGUID("tag:1", false), "tag:1"},
+		{"link", NewItem("T", "http://x/1", ""), "http://x/1"},
+		{"derived", NewItem("T", "", "D"), "urn:sha1:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewChannel("C", "http://x", "D").AddItem(tt.item).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids [2]string
+			for pass := range ids {
+				out, err := EncodeJSON(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var feed jsonFeed
+				if err := json.Unmarshal(out, &feed); err != nil {
+					t.Fatal(err)
+				}
+				ids[pass] = feed.Items[0].ID
+			}
+			if !strings.HasPrefix(ids[0], tt.want) || ids[0] != ids[1] {
+				t.Errorf("ids = %q, want a stable %q", ids, tt.want)
+			}
+		})
+	}
+	empty := &RSS{channel: Channel{title: "C", items: []Item{{}}}}
+	if _, err := EncodeJSON(empty); !errors.Is(err, ErrFieldNotPopulated) {
+		t.Errorf("item without id or text error = %v, want ErrFieldNotPopulated", err)
+	}
+}
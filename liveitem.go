@@ -0,0 +1,99 @@
+package easyrss
+
+import (
+	"strings"
+	"time"
+)
+
+//Scheduling states of a live item
+const (
+	LivePending = "pending"
+	LiveLive    = "live"
+	LiveEnded   = "ended"
+)
+
+//A Podcasting 2.0 <podcast:liveItem>, a scheduled or ongoing live stream. It carries the same fields as a regular item, so all Item
+//accessors work on it, plus its schedule.
+type LiveItem struct {
+	Item
+	status string
+	start  *time.Time
+	end    *time.Time
+}
+
+//A <podcast:contentLink>, somewhere other than the enclosure the content can be watched or listened to, e.g. a video platform
+type ContentLink struct {
+	Href string
+	Text string
+}
+
+func parseLiveItem(n node) LiveItem {
+	live := LiveItem{
+		status: strings.ToLower(strings.TrimSpace(n.Attr("status"))),
+		start:  parseDate(n.Attr("start")),
+		end:    parseDate(n.Attr("end")),
+	}
+	getItemMeta(&live.Item, n)
+	return live
+}
+
+//Returns the status declared by the feed: LivePending, LiveLive or LiveEnded. Feeds aren't always refreshed when a stream starts
+//or stops, see State for an estimate based on the schedule.
+func (l *LiveItem) Status() (string, error) {
+	if l.status == "" {
+		return "", notPopulated(ElementLiveItem, NamespacePodcast, "status")
+	}
+	return l.status, nil
+}
+
+//Returns when the stream is scheduled to start. If the start time is missing or couldn't be parsed, will return nil and an error.
+func (l *LiveItem) Start() (*time.Time, error) {
+	if l.start == nil {
+		return nil, notPopulated(ElementLiveItem, NamespacePodcast, "start")
+	}
+	return l.start, nil
+}
+
+//Returns when the stream is scheduled to end. If the end time is missing or couldn't be parsed, will return nil and an error.
+func (l *LiveItem) End() (*time.Time, error) {
+	if l.end == nil {
+		return nil, notPopulated(ElementLiveItem, NamespacePodcast, "end")
+	}
+	return l.end, nil
+}
+
+//Returns the scheduling state at now. An ended status is final, otherwise the schedule wins over a status that may be stale.
+func (l *LiveItem) State(now time.Time) string {
+	switch {
+	case l.status == LiveEnded:
+		return LiveEnded
+	case l.end != nil && !now.Before(*l.end):
+		return LiveEnded
+	case l.start != nil && now.Before(*l.start):
+		return LivePending
+	case l.start != nil:
+		return LiveLive
+	case l.status != "":
+		return l.status
+	}
+	return LivePending
+}
+
+//Returns the channel's live items, in feed order. If the feed has none, will return nil and an error.
+func (r *RSS) LiveItems() ([]LiveItem, error) {
+	if len(r.channel.liveItems) == 0 {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "liveItem")
+	}
+	return r.channel.liveItems, nil
+}
+
+//Returns the places other than the enclosure where the item can be played. If the item doesn't contain Podcasting 2.0 Extensions or has no "contentLink" tags, will return nil and an error.
+func (i *Item) PodcastContentLinks() ([]ContentLink, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(i.podcast.contentLinks) == 0 {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "contentLink")
+	}
+	return i.podcast.contentLinks, nil
+}
@@ -1,62 +1,728 @@
 package easyrss
 
 import (
-	"errors"
-	"github.com/moovweb/gokogiri/xml"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //MediaRSS Item Metadata
 type MediaMeta struct {
-	content   RSSEnclosure
-	credits   map[string]string //Media Credits... Usually role->photographer
-	thumbnail Image
+	contents []MediaContent
+	groups   []MediaGroup
+	details  MediaDetails //Apply to every content object of the item unless overridden
+}
+
+//Optional MediaRSS elements. They may be given for a whole item, a <media:group> or a single <media:content>, the most specific one
+//winning.
+type MediaDetails struct {
+	Title           string
+	TitleType       string //"plain" or "html". Defaults to plain.
+	Description     string
+	DescriptionType string //"plain" or "html". Defaults to plain.
+	Keywords        []string
+	Thumbnails      []MediaThumbnail //In feed order, the first one being the most important
+	Credits         []MediaCredit    //In feed order
+	Player          *MediaPlayer
+	Embed           *MediaEmbed
+	Texts           []MediaText //Timed transcript, in feed order
+	SubTitles       []MediaSubTitle
+	Ratings         []MediaRating
+	Restrictions    []MediaRestriction
+	License         *MediaLicense
+	Copyright       *MediaCopyright
+	Status          *MediaStatus
+	Prices          []MediaPrice
+	Community       *MediaCommunity
+	Hashes          []MediaHash
+	PeerLinks       []MediaPeerLink
+	Locations       []MediaLocation
+	Scenes          []MediaScene
+}
+
+//A <media:thumbnail>. Several may be given for different points in the media.
+type MediaThumbnail struct {
+	Image
+	Time time.Duration //Offset into the media the thumbnail was taken at, 0 if not given
+}
+
+//A <media:player>, a web page the media can be played in
+type MediaPlayer struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+//A <media:embed>, how to embed the media in a page
+type MediaEmbed struct {
+	URL    string
+	Width  int
+	Height int
+	Params map[string]string //From the <media:param> children, keyed by name
+}
+
+//A <media:text> segment, e.g. a transcript line or closed caption
+type MediaText struct {
+	Text  string
+	Type  string //"plain" or "html". Defaults to plain.
+	Lang  string
+	Start time.Duration //Offset the text starts being relevant at
+	End   time.Duration //Offset it stops being relevant at, 0 if not given
+}
+
+//A <media:subTitle> link to a subtitle file
+type MediaSubTitle struct {
+	URL  string
+	Type string //MIME type, e.g. "application/smil"
+	Lang string
+}
+
+//A MediaRSS <media:group>, alternative renditions of the same media. Contents holds them as written in the feed, use Renditions
+//to have them inherit the group's details.
+type MediaGroup struct {
+	MediaDetails
+	Contents []MediaContent
+}
+
+//Constraints for picking a rendition with BestRendition. Zero values don't constrain anything.
+type MediaConstraints struct {
+	MaxHeight  int
+	MaxBitrate float64  //Kilobits per second
+	Types      []string //Acceptable MIME types, most preferred first
+	Medium     string   //Required medium, e.g. "video"
+}
+
+//A MediaRSS <media:content> object
+type MediaContent struct {
+	MediaDetails
+	URL          string
+	FileSize     uint64  //Size in bytes
+	Type         string  //MIME type
+	Medium       string  //image, audio, video, document or executable
+	IsDefault    bool    //Whether this is the default object among its alternatives
+	Expression   string  //full, sample or nonstop. Defaults to full.
+	Bitrate      float64 //Kilobits per second
+	Framerate    float64 //Frames per second
+	SamplingRate float64 //Thousands of samples per second
+	Channels     int     //Audio channels
+	Duration     time.Duration
+	Height       int
+	Width        int
+	Lang         string
+}
+
+//A <media:rating>, e.g. {"urn:mpaa", "pg-13"}
+type MediaRating struct {
+	Scheme string //urn:simple, urn:mpaa, urn:v-chip, urn:icra or urn:tv. Defaults to urn:simple.
+	Value  string //For urn:simple, "adult" or "nonadult"
+}
+
+//A <media:restriction> on where the media may be played or whether it may be shared
+type MediaRestriction struct {
+	Relationship string   //"allow" or "deny"
+	Type         string   //"country", "uri" or "sharing"
+	Values       []string //ISO 3166 country codes or URIs. "all" and "none" stand for every and no value.
+}
+
+//A <media:license>
+type MediaLicense struct {
+	Name string
+	Type string //MIME type of the license document
+	Href string
+}
+
+//A <media:copyright>
+type MediaCopyright struct {
+	Text string
+	URL  string //Terms of use
+}
+
+//A <media:status>. Media isn't playable once blocked or deleted.
+type MediaStatus struct {
+	State  string //"active", "blocked" or "deleted"
+	Reason string //Free text or a URI explaining the state
+}
+
+//A <media:price>. Media without a price is free.
+type MediaPrice struct {
+	Type     string //"rent", "purchase", "package" or "subscription"
+	Price    float64
+	Currency string //ISO 4217 code
+	Info     string //URL with more details, e.g. the package contents
+}
+
+//A <media:community>, how users have received the media
+type MediaCommunity struct {
+	StarAverage float64 //Average star rating, between StarMin and StarMax
+	StarCount   int     //Number of ratings
+	StarMin     float64
+	StarMax     float64
+	Views       uint64
+	Favorites   uint64
+	Tags        []MediaTag
+}
+
+//A <media:credit>, someone who contributed to the media
+type MediaCredit struct {
+	Role   string //e.g. "actor" or "photographer", empty if not given
+	Scheme string //Role vocabulary. Defaults to urn:ebu.
+	Name   string
+}
+
+//A user tag from <media:tags>
+type MediaTag struct {
+	Name   string
+	Weight int //Defaults to 1
+}
+
+//A <media:hash> of the media file, as a hex string
+type MediaHash struct {
+	Algorithm string //"md5" or "sha-1". Defaults to md5.
+	Value     string
+}
+
+//A <media:peerLink>, a peer-to-peer source for the media such as a torrent
+type MediaPeerLink struct {
+	Type string //MIME type, e.g. "application/x-bittorrent"
+	URL  string
+}
+
+//A <media:location>, a place shown or mentioned in the media
+type MediaLocation struct {
+	Description string
+	Start       time.Duration //Offset the location becomes relevant at
+	End         time.Duration //Offset it stops being relevant at, 0 if not given
+	Point       *GeoPoint     //From a GeoRSS point or GML position, nil if not given
+}
+
+//A WGS84 position
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+//A <media:scene>, a segment of the media
+type MediaScene struct {
+	Title       string
+	Description string
+	Start       time.Duration
+	End         time.Duration //0 if not given
 }
 
 //MediaRSS Channel Metadata
 type MediaChannelMeta struct {
-	rating     string   //Age Rating
-	copyright  string   //Feed Copyright
-	thumbnail  Image    //Feed Thumbnail
-	keywords   []string //Feed Keywords
-	categories []string //Feed Categories
+	rating       string             //Age Rating
+	copyright    string             //Feed Copyright
+	thumbnail    Image              //Feed Thumbnail
+	keywords     []string           //Feed Keywords
+	categories   []string           //Feed Categories
+	restrictions []MediaRestriction //Apply to every item that doesn't have its own
 }
 
-func setMediaMetaField(n xml.Node, m *MediaMeta) {
+func setMediaMetaField(n node, m *MediaMeta) {
 	tag := n.Name()
 	switch tag {
 	case "content":
-		if urlAttr := n.Attribute("url"); urlAttr != nil {
-			m.content.url = urlAttr.Value()
+		m.contents = append(m.contents, parseMediaContent(n))
+	case "group":
+		group := MediaGroup{}
+		for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+			if activeElem.Namespace() != mrssNS {
+				continue
+			}
+			if activeElem.Name() == "content" {
+				group.Contents = append(group.Contents, parseMediaContent(activeElem))
+			} else {
+				setMediaDetailsField(activeElem, &group.MediaDetails)
+			}
 		}
-		if typeAttr := n.Attribute("type"); typeAttr != nil {
-			m.content.url = typeAttr.Value()
+		m.groups = append(m.groups, group)
+	default:
+		setMediaDetailsField(n, &m.details)
+	}
+}
+
+//Sets the optional element n on an item, group or content object
+func setMediaDetailsField(n node, d *MediaDetails) {
+	switch n.Name() {
+	case "title":
+		d.Title, d.TitleType = strings.TrimSpace(n.Content()), mediaTextType(n)
+	case "description":
+		d.Description, d.DescriptionType = strings.TrimSpace(n.Content()), mediaTextType(n)
+	case "keywords":
+		d.Keywords = splitKeywords(n.Content())
+	case "thumbnail":
+		thumbnail := MediaThumbnail{Time: parseNPT(n.Attr("time"))}
+		setMediaThumbnail(n, &thumbnail.Image)
+		if thumbnail.url != "" {
+			d.Thumbnails = append(d.Thumbnails, thumbnail)
 		}
-		if sizeAttr := n.Attribute("fileSize"); sizeAttr != nil {
-			fSize := sizeAttr.Value()
-			m.content.size, _ = strconv.ParseUint(fSize, 10, 64)
+	case "player":
+		d.Player = &MediaPlayer{URL: n.Attr("url")}
+		d.Player.Width, _ = strconv.Atoi(strings.TrimSpace(n.Attr("width")))
+		d.Player.Height, _ = strconv.Atoi(strings.TrimSpace(n.Attr("height")))
+	case "embed":
+		d.Embed = &MediaEmbed{URL: n.Attr("url")}
+		d.Embed.Width, _ = strconv.Atoi(strings.TrimSpace(n.Attr("width")))
+		d.Embed.Height, _ = strconv.Atoi(strings.TrimSpace(n.Attr("height")))
+		for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+			if activeElem.Name() == "param" && activeElem.Attr("name") != "" {
+				if d.Embed.Params == nil {
+					d.Embed.Params = make(map[string]string)
+				}
+				d.Embed.Params[activeElem.Attr("name")] = strings.TrimSpace(activeElem.Content())
+			}
 		}
-	case "thumbnail":
-		if urlAttr := n.Attribute("url"); urlAttr != nil {
-			m.thumbnail.url = urlAttr.Value()
+	case "text":
+		d.Texts = append(d.Texts, MediaText{
+			Text:  strings.TrimSpace(n.Content()),
+			Type:  mediaTextType(n),
+			Lang:  n.Attr("lang"),
+			Start: parseNPT(n.Attr("start")),
+			End:   parseNPT(n.Attr("end")),
+		})
+	case "subTitle":
+		d.SubTitles = append(d.SubTitles, MediaSubTitle{URL: n.Attr("href"), Type: n.Attr("type"), Lang: n.Attr("lang")})
+	case "rating":
+		rating := MediaRating{Scheme: strings.ToLower(strings.TrimSpace(n.Attr("scheme"))), Value: strings.ToLower(strings.TrimSpace(n.Content()))}
+		if rating.Scheme == "" {
+			rating.Scheme = "urn:simple"
+		}
+		d.Ratings = append(d.Ratings, rating)
+	case "restriction":
+		d.Restrictions = append(d.Restrictions, parseMediaRestriction(n))
+	case "license":
+		d.License = &MediaLicense{Name: strings.TrimSpace(n.Content()), Type: n.Attr("type"), Href: n.Attr("href")}
+	case "copyright":
+		d.Copyright = &MediaCopyright{Text: strings.TrimSpace(n.Content()), URL: n.Attr("url")}
+	case "status":
+		d.Status = &MediaStatus{State: strings.ToLower(strings.TrimSpace(n.Attr("state"))), Reason: n.Attr("reason")}
+	case "price":
+		price := MediaPrice{Type: strings.ToLower(n.Attr("type")), Currency: strings.ToUpper(n.Attr("currency")), Info: n.Attr("info")}
+		price.Price, _ = strconv.ParseFloat(strings.TrimSpace(n.Attr("price")), 64)
+		d.Prices = append(d.Prices, price)
+	case "community":
+		d.Community = parseMediaCommunity(n)
+	case "hash":
+		mediaHash := MediaHash{Algorithm: strings.ToLower(strings.TrimSpace(n.Attr("algo"))), Value: strings.ToLower(strings.TrimSpace(n.Content()))}
+		if mediaHash.Algorithm == "" {
+			mediaHash.Algorithm = "md5"
+		}
+		d.Hashes = append(d.Hashes, mediaHash)
+	case "peerLink":
+		d.PeerLinks = append(d.PeerLinks, MediaPeerLink{Type: n.Attr("type"), URL: n.Attr("href")})
+	case "location":
+		location := MediaLocation{Description: n.Attr("description"), Start: parseNPT(n.Attr("start")), End: parseNPT(n.Attr("end"))}
+		if position := findDescendant(n, "point", "pos"); position != nil {
+			location.Point = parseGeoPoint(position.Content())
+		}
+		d.Locations = append(d.Locations, location)
+	case "scenes":
+		for scene := n.FirstChild(); scene != nil; scene = scene.NextSibling() {
+			if scene.Name() == "scene" {
+				d.Scenes = append(d.Scenes, parseMediaScene(scene))
+			}
+		}
+	case "credit":
+		credit := MediaCredit{Role: strings.ToLower(strings.TrimSpace(n.Attr("role"))), Scheme: strings.TrimSpace(n.Attr("scheme")), Name: strings.TrimSpace(n.Content())}
+		if credit.Scheme == "" {
+			credit.Scheme = "urn:ebu"
+		}
+		if credit.Name != "" {
+			d.Credits = append(d.Credits, credit)
+		}
+	}
+}
+
+//Returns the credits with the given role, compared case-insensitively, in feed order. Returns nil if there are none.
+func (d *MediaDetails) CreditsFor(role string) []MediaCredit {
+	var credits []MediaCredit
+	for _, credit := range d.Credits {
+		if strings.EqualFold(credit.Role, role) {
+			credits = append(credits, credit)
+		}
+	}
+	return credits
+}
+
+func parseMediaCommunity(n node) *MediaCommunity {
+	community := &MediaCommunity{}
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		switch activeElem.Name() {
+		case "starRating":
+			community.StarAverage, _ = strconv.ParseFloat(strings.TrimSpace(activeElem.Attr("average")), 64)
+			community.StarCount, _ = strconv.Atoi(strings.TrimSpace(activeElem.Attr("count")))
+			community.StarMin, _ = strconv.ParseFloat(strings.TrimSpace(activeElem.Attr("min")), 64)
+			community.StarMax, _ = strconv.ParseFloat(strings.TrimSpace(activeElem.Attr("max")), 64)
+		case "statistics":
+			community.Views, _ = strconv.ParseUint(strings.TrimSpace(activeElem.Attr("views")), 10, 64)
+			community.Favorites, _ = strconv.ParseUint(strings.TrimSpace(activeElem.Attr("favorites")), 10, 64)
+		case "tags":
+			for _, tag := range strings.Split(activeElem.Content(), ",") { //"name: weight" pairs, the weight being optional
+				name, weight := tag, 1
+				if colon := strings.LastIndexByte(tag, ':'); colon >= 0 {
+					if parsed, err := strconv.Atoi(strings.TrimSpace(tag[colon+1:])); err == nil {
+						name, weight = tag[:colon], parsed
+					}
+				}
+				if name = strings.TrimSpace(name); name != "" {
+					community.Tags = append(community.Tags, MediaTag{Name: name, Weight: weight})
+				}
+			}
+		}
+	}
+	return community
+}
+
+func parseMediaScene(n node) MediaScene {
+	scene := MediaScene{}
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		tagContent := strings.TrimSpace(activeElem.Content())
+		switch activeElem.Name() {
+		case "sceneTitle":
+			scene.Title = tagContent
+		case "sceneDescription":
+			scene.Description = tagContent
+		case "sceneStartTime":
+			scene.Start = parseNPT(tagContent)
+		case "sceneEndTime":
+			scene.End = parseNPT(tagContent)
+		}
+	}
+	return scene
+}
+
+//Returns the first element below n, depth first, whose local name is one of names
+func findDescendant(n node, names ...string) node {
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		for _, name := range names {
+			if child.Name() == name {
+				return child
+			}
+		}
+		if found := findDescendant(child, names...); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+//Parses a "latitude longitude" pair as used by GeoRSS and GML. Returns nil if it isn't one.
+func parseGeoPoint(position string) *GeoPoint {
+	fields := strings.Fields(position)
+	if len(fields) != 2 {
+		return nil
+	}
+	lat, latErr := strconv.ParseFloat(fields[0], 64)
+	lon, lonErr := strconv.ParseFloat(fields[1], 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil
+	}
+	return &GeoPoint{Lat: lat, Lon: lon}
+}
+
+//Checks downloaded media against its hashes. Every hash must match. Returns an error if there is no hash, if a hash uses an
+//algorithm other than md5 or sha-1, or if data doesn't match.
+func (c *MediaContent) Verify(data []byte) error {
+	return c.VerifyReader(bytes.NewReader(data))
+}
+
+//Same as Verify, but reads the media from r so large files don't need to be held in memory.
+func (c *MediaContent) VerifyReader(r io.Reader) error {
+	if len(c.Hashes) == 0 {
+		return notPopulated(ElementMediaContent, NamespaceMRSS, "hash")
+	}
+	hashers := make([]hash.Hash, len(c.Hashes))
+	writers := make([]io.Writer, len(c.Hashes))
+	for h, mediaHash := range c.Hashes {
+		switch mediaHash.Algorithm {
+		case "md5":
+			hashers[h] = md5.New()
+		case "sha-1", "sha1":
+			hashers[h] = sha1.New()
+		default:
+			return fmt.Errorf("%w: %q", ErrUnsupportedIntegrity, mediaHash.Algorithm)
+		}
+		writers[h] = hashers[h]
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return err
+	}
+	for h, mediaHash := range c.Hashes {
+		expected, err := hex.DecodeString(mediaHash.Value)
+		if err != nil || !bytes.Equal(expected, hashers[h].Sum(nil)) {
+			return ErrIntegrityMismatch
+		}
+	}
+	return nil
+}
+
+func parseMediaRestriction(n node) MediaRestriction {
+	return MediaRestriction{
+		Relationship: strings.ToLower(strings.TrimSpace(n.Attr("relationship"))),
+		Type:         strings.ToLower(strings.TrimSpace(n.Attr("type"))),
+		Values:       strings.Fields(n.Content()),
+	}
+}
+
+//Whether the restriction lets value through. Restrictions of another type always do.
+func (r *MediaRestriction) permits(restrictionType, value string) bool {
+	if r.Type != restrictionType {
+		return true
+	}
+	listed := false
+	for _, v := range r.Values {
+		if strings.EqualFold(v, "all") || strings.EqualFold(v, value) {
+			listed = true
+		}
+	}
+	if r.Relationship == "deny" {
+		return !listed
+	}
+	return listed
+}
+
+//Whether the media may be played in country (an ISO 3166 code) on platform (a URI), according to its status and restrictions.
+//Pass an empty string to skip the corresponding check.
+func (d *MediaDetails) Playable(country, platform string) bool {
+	if d.Status != nil && (d.Status.State == "blocked" || d.Status.State == "deleted") {
+		return false
+	}
+	for r := range d.Restrictions {
+		if country != "" && !d.Restrictions[r].permits("country", country) || platform != "" && !d.Restrictions[r].permits("uri", platform) {
+			return false
+		}
+	}
+	return true
+}
+
+//Whether the media may be shared or embedded elsewhere, i.e. no restriction denies sharing
+func (d *MediaDetails) Shareable() bool {
+	for _, restriction := range d.Restrictions {
+		if restriction.Type == "sharing" && restriction.Relationship == "deny" {
+			return false
+		}
+	}
+	return true
+}
+
+//The type attribute of a title, description or text element, "plain" when missing
+func mediaTextType(n node) string {
+	if textType := strings.ToLower(strings.TrimSpace(n.Attr("type"))); textType != "" {
+		return textType
+	}
+	return "plain"
+}
+
+//Parses a Normal Play Time offset such as "12:05:01.123" or "65.5". Returns 0 for anything else, including "now".
+func parseNPT(attr string) time.Duration {
+	attr = strings.TrimSpace(attr)
+	if strings.Contains(attr, ":") {
+		offset, _ := parseCueTime(attr)
+		return offset
+	}
+	return parseSeconds(attr)
+}
+
+func (d *MediaDetails) isEmpty() bool {
+	return d.Title == "" && d.Description == "" && len(d.Keywords) == 0 && len(d.Thumbnails) == 0 && len(d.Credits) == 0 &&
+		d.Player == nil && d.Embed == nil && len(d.Texts) == 0 && len(d.SubTitles) == 0 && len(d.Ratings) == 0 &&
+		len(d.Restrictions) == 0 && d.License == nil && d.Copyright == nil && d.Status == nil && len(d.Prices) == 0 &&
+		d.Community == nil && len(d.Hashes) == 0 && len(d.PeerLinks) == 0 && len(d.Locations) == 0 && len(d.Scenes) == 0
+}
+
+//Returns d with the fields it doesn't set taken from parent
+func (d MediaDetails) inherit(parent *MediaDetails) MediaDetails {
+	if d.Title == "" {
+		d.Title, d.TitleType = parent.Title, parent.TitleType
+	}
+	if d.Description == "" {
+		d.Description, d.DescriptionType = parent.Description, parent.DescriptionType
+	}
+	if len(d.Keywords) == 0 {
+		d.Keywords = parent.Keywords
+	}
+	if len(d.Thumbnails) == 0 {
+		d.Thumbnails = parent.Thumbnails
+	}
+	if len(d.Credits) == 0 {
+		d.Credits = parent.Credits
+	}
+	if d.Player == nil {
+		d.Player = parent.Player
+	}
+	if d.Embed == nil {
+		d.Embed = parent.Embed
+	}
+	if len(d.Texts) == 0 {
+		d.Texts = parent.Texts
+	}
+	if len(d.SubTitles) == 0 {
+		d.SubTitles = parent.SubTitles
+	}
+	if len(d.Ratings) == 0 {
+		d.Ratings = parent.Ratings
+	}
+	if len(d.Restrictions) == 0 {
+		d.Restrictions = parent.Restrictions
+	}
+	if d.License == nil {
+		d.License = parent.License
+	}
+	if d.Copyright == nil {
+		d.Copyright = parent.Copyright
+	}
+	if d.Status == nil {
+		d.Status = parent.Status
+	}
+	if len(d.Prices) == 0 {
+		d.Prices = parent.Prices
+	}
+	if d.Community == nil {
+		d.Community = parent.Community
+	}
+	if len(d.Hashes) == 0 {
+		d.Hashes = parent.Hashes
+	}
+	if len(d.PeerLinks) == 0 {
+		d.PeerLinks = parent.PeerLinks
+	}
+	if len(d.Locations) == 0 {
+		d.Locations = parent.Locations
+	}
+	if len(d.Scenes) == 0 {
+		d.Scenes = parent.Scenes
+	}
+	return d
+}
+
+//Returns a copy of m that shares nothing with it
+func (m MediaMeta) clone() MediaMeta {
+	m.details = m.details.clone()
+	m.contents = cloneMediaContents(m.contents)
+	if m.groups != nil {
+		groups := make([]MediaGroup, len(m.groups))
+		for g, group := range m.groups {
+			groups[g] = MediaGroup{MediaDetails: group.MediaDetails.clone(), Contents: cloneMediaContents(group.Contents)}
 		}
-		if widthAttr := n.Attribute("width"); widthAttr != nil {
-			width, _ := strconv.ParseInt(widthAttr.Value(), 10, 8)
-			m.thumbnail.width = int(width)
+		m.groups = groups
+	}
+	return m
+}
+
+func cloneMediaContents(contents []MediaContent) []MediaContent {
+	cloned := append([]MediaContent(nil), contents...)
+	for c := range cloned {
+		cloned[c].MediaDetails = cloned[c].MediaDetails.clone()
+	}
+	return cloned
+}
+
+//Returns a copy of d that shares no slices, maps or pointers with it
+func (d MediaDetails) clone() MediaDetails {
+	d.Keywords = append([]string(nil), d.Keywords...)
+	d.Thumbnails = append([]MediaThumbnail(nil), d.Thumbnails...)
+	d.Credits = append([]MediaCredit(nil), d.Credits...)
+	if d.Player != nil {
+		player := *d.Player
+		d.Player = &player
+	}
+	if d.Embed != nil {
+		embed := *d.Embed
+		if d.Embed.Params != nil {
+			embed.Params = make(map[string]string, len(d.Embed.Params))
+			for name, value := range d.Embed.Params {
+				embed.Params[name] = value
+			}
 		}
-		if heightAttr := n.Attribute("height"); heightAttr != nil {
-			height, _ := strconv.ParseInt(heightAttr.Value(), 10, 8)
-			m.thumbnail.height = int(height)
+		d.Embed = &embed
+	}
+	d.Texts = append([]MediaText(nil), d.Texts...)
+	d.SubTitles = append([]MediaSubTitle(nil), d.SubTitles...)
+	d.Ratings = append([]MediaRating(nil), d.Ratings...)
+	d.Restrictions = append([]MediaRestriction(nil), d.Restrictions...)
+	for r := range d.Restrictions {
+		d.Restrictions[r].Values = append([]string(nil), d.Restrictions[r].Values...)
+	}
+	if d.License != nil {
+		license := *d.License
+		d.License = &license
+	}
+	if d.Copyright != nil {
+		copyright := *d.Copyright
+		d.Copyright = &copyright
+	}
+	if d.Status != nil {
+		status := *d.Status
+		d.Status = &status
+	}
+	d.Prices = append([]MediaPrice(nil), d.Prices...)
+	if d.Community != nil {
+		community := *d.Community
+		community.Tags = append([]MediaTag(nil), d.Community.Tags...)
+		d.Community = &community
+	}
+	d.Hashes = append([]MediaHash(nil), d.Hashes...)
+	d.PeerLinks = append([]MediaPeerLink(nil), d.PeerLinks...)
+	d.Locations = append([]MediaLocation(nil), d.Locations...)
+	for l := range d.Locations {
+		if point := d.Locations[l].Point; point != nil {
+			copied := *point
+			d.Locations[l].Point = &copied
 		}
-	case "credits":
-		if roleAttr := n.Attribute("role"); roleAttr != nil {
-			m.credits[roleAttr.Value()] = n.Content()
+	}
+	d.Scenes = append([]MediaScene(nil), d.Scenes...)
+	return d
+}
+
+func parseMediaContent(n node) MediaContent {
+	content := MediaContent{
+		URL:        n.Attr("url"),
+		Type:       n.Attr("type"),
+		Medium:     strings.ToLower(n.Attr("medium")),
+		IsDefault:  strings.EqualFold(strings.TrimSpace(n.Attr("isDefault")), "true"),
+		Expression: strings.ToLower(n.Attr("expression")),
+		Duration:   parseSeconds(n.Attr("duration")),
+		Lang:       n.Attr("lang"),
+	}
+	if content.Expression == "" {
+		content.Expression = "full"
+	}
+	content.FileSize, _ = strconv.ParseUint(strings.TrimSpace(n.Attr("fileSize")), 10, 64)
+	content.Bitrate, _ = strconv.ParseFloat(strings.TrimSpace(n.Attr("bitrate")), 64)
+	content.Framerate, _ = strconv.ParseFloat(strings.TrimSpace(n.Attr("framerate")), 64)
+	content.SamplingRate, _ = strconv.ParseFloat(strings.TrimSpace(n.Attr("samplingrate")), 64)
+	content.Channels, _ = strconv.Atoi(strings.TrimSpace(n.Attr("channels")))
+	content.Height, _ = strconv.Atoi(strings.TrimSpace(n.Attr("height")))
+	content.Width, _ = strconv.Atoi(strings.TrimSpace(n.Attr("width")))
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		if activeElem.Namespace() == mrssNS {
+			setMediaDetailsField(activeElem, &content.MediaDetails)
 		}
 	}
+	return content
 }
 
-func setMediaChannelMetaField(n xml.Node, m *MediaChannelMeta) {
+func setMediaThumbnail(n node, i *Image) {
+	if urlAttr := n.Attr("url"); urlAttr != "" {
+		i.url = urlAttr
+	}
+	if widthAttr := n.Attr("width"); widthAttr != "" {
+		i.width, _ = strconv.Atoi(strings.TrimSpace(widthAttr))
+	}
+	if heightAttr := n.Attr("height"); heightAttr != "" {
+		i.height, _ = strconv.Atoi(strings.TrimSpace(heightAttr))
+	}
+}
+
+func setMediaChannelMetaField(n node, m *MediaChannelMeta) {
 	tag := n.Name()
 	tagContent := n.Content()
 	switch tag {
@@ -65,21 +731,13 @@ func setMediaChannelMetaField(n xml.Node, m *MediaChannelMeta) {
 	case "copyright":
 		m.copyright = tagContent
 	case "thumbnail":
-		if urlAttr := n.Attribute("url"); urlAttr != nil {
-			m.thumbnail.url = urlAttr.Value()
-		}
-		if widthAttr := n.Attribute("width"); widthAttr != nil {
-			width, _ := strconv.ParseInt(widthAttr.Value(), 10, 8)
-			m.thumbnail.width = int(width)
-		}
-		if heightAttr := n.Attribute("height"); heightAttr != nil {
-			height, _ := strconv.ParseInt(heightAttr.Value(), 10, 8)
-			m.thumbnail.height = int(height)
-		}
+		setMediaThumbnail(n, &m.thumbnail)
 	case "keywords":
-		m.keywords = strings.Split(tagContent, ", ")
+		m.keywords = splitKeywords(tagContent)
 	case "category":
 		m.categories = append(m.categories, tagContent)
+	case "restriction":
+		m.restrictions = append(m.restrictions, parseMediaRestriction(n))
 	}
 }
 
@@ -91,10 +749,10 @@ func (r *RSS) IsMRSS() bool {
 //MediaRSS Feed Rating. If the MRSS feed "rating" field is not populated or if the feed doesn't implement MediaRSS extensions, you'll receive an empty string and an error.
 func (r *RSS) MRSSRating() (string, error) {
 	if !r.channel.isMRSS {
-		return "", errors.New("Not a MediaRSS Feed")
+		return "", ErrNotMRSS
 	}
 	if r.channel.media.rating == "" {
-		return "", errors.New("MediaRSS feed rating field is not populated")
+		return "", notPopulated(ElementChannel, NamespaceMRSS, "rating")
 	}
 	return r.channel.media.rating, nil
 }
@@ -102,29 +760,29 @@ func (r *RSS) MRSSRating() (string, error) {
 //MediaRSS Feed Copyright. If the MRSS feed "copyright" field is not populated or if the feed doesn't implement MediaRSS extensions, you'll receive an empty string and an error.
 func (r *RSS) MRSSCopyright() (string, error) {
 	if !r.channel.isMRSS {
-		return "", errors.New("Not a MediaRSS Feed")
+		return "", ErrNotMRSS
 	} else if r.channel.media.copyright == "" {
-		return "", errors.New("MediaRSS feed copyright field is not populated")
+		return "", notPopulated(ElementChannel, NamespaceMRSS, "copyright")
 	}
 	return r.channel.media.copyright, nil
 }
 
-//Returns the Itunes "image" field for the channel. If the channel doesn't contain ITunes Extensions or hasn't populated the channel-wide Itunes "image" field, will return nil and an error.
+//MediaRSS Feed thumbnail. If the MRSS feed "thumbnail" field is not populated or if the feed doesn't implement MediaRSS extensions, you'll receive nil and an error.
 func (r *RSS) Thumbnail() (*Image, error) {
 	if !r.channel.isMRSS {
-		return nil, errors.New("Not a MediaRSS Feed")
+		return nil, ErrNotMRSS
 	} else if r.channel.media.thumbnail.url == "" {
-		return nil, errors.New("MediaRSS thumbnail fields not populated")
+		return nil, notPopulated(ElementChannel, NamespaceMRSS, "thumbnail")
 	}
-	return &r.channel.itunes.image, nil
+	return &r.channel.media.thumbnail, nil
 }
 
 //MediaRSS Feed keywords. If the MRSS feed "keywords" field is not populated or if the feed doesn't implement MediaRSS extensions, this will return nil and an error.
 func (r *RSS) Keywords() ([]string, error) {
 	if !r.channel.isMRSS {
-		return nil, errors.New("Not a MediaRSS Feed")
+		return nil, ErrNotMRSS
 	} else if len(r.channel.media.keywords) == 0 {
-		return nil, errors.New("No MediaRSS Feed Keywords")
+		return nil, notPopulated(ElementChannel, NamespaceMRSS, "keywords")
 	}
 	return r.channel.media.keywords, nil
 }
@@ -132,9 +790,382 @@ func (r *RSS) Keywords() ([]string, error) {
 //MediaRSS Feed categories. If the MRSS feed "categories" field is not populated or if the feed doesn't implement MediaRSS extensions, this will return nil and an error.
 func (r *RSS) MRSSCategories() ([]string, error) {
 	if !r.channel.isMRSS {
-		return nil, errors.New("Not a MediaRSS Feed")
+		return nil, ErrNotMRSS
 	} else if len(r.channel.media.categories) == 0 {
-		return nil, errors.New("No MediaRSS Feed Categories")
+		return nil, notPopulated(ElementChannel, NamespaceMRSS, "category")
 	}
 	return r.channel.media.categories, nil
 }
+
+//MediaRSS feed restrictions, which apply to every item without restrictions of its own. If the feed has no "restriction" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (r *RSS) MRSSRestrictions() ([]MediaRestriction, error) {
+	if !r.channel.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(r.channel.media.restrictions) == 0 {
+		return nil, notPopulated(ElementChannel, NamespaceMRSS, "restriction")
+	}
+	return r.channel.media.restrictions, nil
+}
+
+//Whether the item may be played in country (an ISO 3166 code) on platform (a URI). The item's own status and restrictions are
+//checked, falling back to the feed's restrictions when it has none. Pass an empty string to skip the corresponding check. Items
+//without MediaRSS extensions are playable unless the feed restricts them. Use MediaRenditions and MediaDetails.Playable to
+//check a single rendition.
+func (r *RSS) MediaPlayable(i *Item, country, platform string) bool {
+	details := i.media.details.inherit(&MediaDetails{Restrictions: r.channel.media.restrictions})
+	return details.Playable(country, platform)
+}
+
+//MediaRSS content objects of the item, in feed order. If the item has no "content" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaContents() ([]MediaContent, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.contents) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "content")
+	}
+	return i.media.contents, nil
+}
+
+//The item's default MediaRSS content object: the one marked isDefault, or the first one. If the item has no "content" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaContent() (*MediaContent, error) {
+	contents, err := i.MediaContents()
+	if err != nil {
+		return nil, err
+	}
+	for c := range contents {
+		if contents[c].IsDefault {
+			return &contents[c], nil
+		}
+	}
+	return &contents[0], nil
+}
+
+//MediaRSS item thumbnail. If the item has no "thumbnail" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaThumbnail() (*Image, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Thumbnails) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "thumbnail")
+	}
+	return &i.media.details.Thumbnails[0].Image, nil
+}
+
+//All MediaRSS item thumbnails, in feed order. If the item has no "thumbnail" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaThumbnails() ([]MediaThumbnail, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Thumbnails) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "thumbnail")
+	}
+	return i.media.details.Thumbnails, nil
+}
+
+//MediaRSS item credits, in feed order. If the item has no "credit" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaCredits() ([]MediaCredit, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Credits) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "credit")
+	}
+	return i.media.details.Credits, nil
+}
+
+//MediaRSS item credits with the given role, e.g. "actor". If the item has no such credit or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaCreditsFor(role string) ([]MediaCredit, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	}
+	credits := i.media.details.CreditsFor(role)
+	if len(credits) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "credit")
+	}
+	return credits, nil
+}
+
+//MediaRSS item title. If the item has no "title" field or doesn't implement MediaRSS extensions, you'll receive an empty string and an error.
+func (i *Item) MediaTitle() (string, error) {
+	if !i.isMRSS {
+		return "", ErrNotMRSS
+	} else if i.media.details.Title == "" {
+		return "", notPopulated(ElementItem, NamespaceMRSS, "title")
+	}
+	return i.media.details.Title, nil
+}
+
+//MediaRSS item description. If the item has no "description" field or doesn't implement MediaRSS extensions, you'll receive an empty string and an error.
+func (i *Item) MediaDescription() (string, error) {
+	if !i.isMRSS {
+		return "", ErrNotMRSS
+	} else if i.media.details.Description == "" {
+		return "", notPopulated(ElementItem, NamespaceMRSS, "description")
+	}
+	return i.media.details.Description, nil
+}
+
+//MediaRSS item keywords. If the item has no "keywords" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaKeywords() ([]string, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Keywords) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "keywords")
+	}
+	return i.media.details.Keywords, nil
+}
+
+//MediaRSS item player. If the item has no "player" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaPlayer() (*MediaPlayer, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.Player == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "player")
+	}
+	return i.media.details.Player, nil
+}
+
+//MediaRSS item embed information. If the item has no "embed" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaEmbed() (*MediaEmbed, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.Embed == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "embed")
+	}
+	return i.media.details.Embed, nil
+}
+
+//MediaRSS item text segments, in feed order. If the item has no "text" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaTexts() ([]MediaText, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Texts) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "text")
+	}
+	return i.media.details.Texts, nil
+}
+
+//MediaRSS item subtitle links. If the item has no "subTitle" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaSubTitles() ([]MediaSubTitle, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.SubTitles) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "subTitle")
+	}
+	return i.media.details.SubTitles, nil
+}
+
+//MediaRSS item ratings. If the item has no "rating" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaRatings() ([]MediaRating, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Ratings) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "rating")
+	}
+	return i.media.details.Ratings, nil
+}
+
+//MediaRSS item restrictions. If the item has no "restriction" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaRestrictions() ([]MediaRestriction, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Restrictions) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "restriction")
+	}
+	return i.media.details.Restrictions, nil
+}
+
+//MediaRSS item license. If the item has no "license" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaLicense() (*MediaLicense, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.License == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "license")
+	}
+	return i.media.details.License, nil
+}
+
+//MediaRSS item copyright. If the item has no "copyright" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaCopyright() (*MediaCopyright, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.Copyright == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "copyright")
+	}
+	return i.media.details.Copyright, nil
+}
+
+//MediaRSS item status. If the item has no "status" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaStatus() (*MediaStatus, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.Status == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "status")
+	}
+	return i.media.details.Status, nil
+}
+
+//MediaRSS item prices. If the item has no "price" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaPrices() ([]MediaPrice, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Prices) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "price")
+	}
+	return i.media.details.Prices, nil
+}
+
+//MediaRSS item community statistics. If the item has no "community" field or doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaCommunity() (*MediaCommunity, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if i.media.details.Community == nil {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "community")
+	}
+	return i.media.details.Community, nil
+}
+
+//MediaRSS item hashes. If the item has no "hash" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaHashes() ([]MediaHash, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Hashes) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "hash")
+	}
+	return i.media.details.Hashes, nil
+}
+
+//MediaRSS item peer-to-peer links. If the item has no "peerLink" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaPeerLinks() ([]MediaPeerLink, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.PeerLinks) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "peerLink")
+	}
+	return i.media.details.PeerLinks, nil
+}
+
+//MediaRSS item locations. If the item has no "location" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaLocations() ([]MediaLocation, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Locations) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "location")
+	}
+	return i.media.details.Locations, nil
+}
+
+//MediaRSS item scenes. If the item has no "scenes" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaScenes() ([]MediaScene, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.details.Scenes) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "scenes")
+	}
+	return i.media.details.Scenes, nil
+}
+
+//Every MediaRSS element given for the item as a whole, including the title and description types. If the item doesn't implement MediaRSS extensions, you'll receive nil and an error.
+func (i *Item) MediaDetails() (*MediaDetails, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	}
+	return &i.media.details, nil
+}
+
+//MediaRSS groups of the item. If the item has no "group" field or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaGroups() ([]MediaGroup, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	} else if len(i.media.groups) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "group")
+	}
+	return i.media.groups, nil
+}
+
+//Returns the group's content objects with the details they don't set themselves taken from the group.
+func (g *MediaGroup) Renditions() []MediaContent {
+	renditions := make([]MediaContent, len(g.Contents))
+	for c, content := range g.Contents {
+		renditions[c] = content
+		renditions[c].MediaDetails = content.MediaDetails.inherit(&g.MediaDetails)
+	}
+	return renditions
+}
+
+//Returns every content object of the item, standalone and grouped, with the details they inherit from their group and the item
+//filled in. If the item has no content objects or doesn't implement MediaRSS extensions, this will return nil and an error.
+func (i *Item) MediaRenditions() ([]MediaContent, error) {
+	if !i.isMRSS {
+		return nil, ErrNotMRSS
+	}
+	var renditions []MediaContent
+	for _, content := range i.media.contents {
+		content.MediaDetails = content.MediaDetails.inherit(&i.media.details)
+		renditions = append(renditions, content)
+	}
+	for g := range i.media.groups {
+		for _, content := range i.media.groups[g].Renditions() {
+			content.MediaDetails = content.MediaDetails.inherit(&i.media.details)
+			renditions = append(renditions, content)
+		}
+	}
+	if len(renditions) == 0 {
+		return nil, notPopulated(ElementItem, NamespaceMRSS, "content")
+	}
+	return renditions, nil
+}
+
+//Picks the rendition of the item that best satisfies c. Renditions with an unacceptable type or medium are never picked. Among the
+//rest, those within the height and bitrate limits win, in order of type preference, then height, then bitrate, ties going to the
+//default one. If every acceptable rendition is over the limits, the smallest is returned. Returns ErrNoRendition if no rendition
+//is acceptable.
+func (i *Item) BestRendition(c MediaConstraints) (*MediaContent, error) {
+	renditions, err := i.MediaRenditions()
+	if err != nil {
+		return nil, err
+	}
+	return bestRendition(renditions, &c)
+}
+
+//Same as Item.BestRendition, but only considers the group's renditions. Details aren't inherited from the item.
+func (g *MediaGroup) BestRendition(c MediaConstraints) (*MediaContent, error) {
+	return bestRendition(g.Renditions(), &c)
+}
+
+func bestRendition(renditions []MediaContent, c *MediaConstraints) (*MediaContent, error) {
+	var best, smallest *MediaContent
+	for r := range renditions {
+		rendition := &renditions[r]
+		if c.Medium != "" && !strings.EqualFold(rendition.Medium, c.Medium) || typeRank(rendition.Type, c.Types) == len(c.Types) && len(c.Types) > 0 {
+			continue
+		}
+		if smallest == nil || rendition.Height < smallest.Height || rendition.Height == smallest.Height && rendition.Bitrate < smallest.Bitrate {
+			smallest = rendition
+		}
+		if c.MaxHeight != 0 && rendition.Height > c.MaxHeight || c.MaxBitrate != 0 && rendition.Bitrate > c.MaxBitrate {
+			continue
+		}
+		if best == nil || renditionBetter(rendition, best, c) {
+			best = rendition
+		}
+	}
+	if best == nil {
+		best = smallest
+	}
+	if best == nil {
+		return nil, ErrNoRendition
+	}
+	return best, nil
+}
+
+//Whether a is a better match for c than b
+func renditionBetter(a, b *MediaContent, c *MediaConstraints) bool {
+	if ra, rb := typeRank(a.Type, c.Types), typeRank(b.Type, c.Types); ra != rb {
+		return ra < rb
+	}
+	if a.Height != b.Height {
+		return a.Height > b.Height
+	}
+	if a.Bitrate != b.Bitrate {
+		return a.Bitrate > b.Bitrate
+	}
+	return a.IsDefault && !b.IsDefault
+}
@@ -0,0 +1,606 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const mrssFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<media:thumbnail url="http://v/c.jpg" width="640" height="360"/>
+<item><title>1</title>
+<media:content url="http://v/1-360.mp4" fileSize="9007199254740993" type="video/mp4" medium="video" bitrate="800" framerate="29.97" duration="185" height="360" width="640" lang="en"/>
+<media:content url="http://v/1-720.mp4" type="video/mp4" medium="Video" isDefault="true" expression="sample" samplingrate="44.1" channels="2" height="720" width="1280"/>
+<media:thumbnail url="http://v/1.jpg" width="1280" height="720"/>
+</item>
+<item><title>2</title><media:content url="http://v/2.mp3"/><media:content url="http://v/2.ogg"/></item>
+<item><title>3</title></item></channel></rss>`
+
+func checkMRSS(t *testing.T, r *RSS) {
+	t.Helper()
+	if thumbnail, err := r.Thumbnail(); err != nil || *thumbnail != (Image{url: "http://v/c.jpg", width: 640, height: 360}) {
+		t.Errorf("channel thumbnail = %+v, %v", thumbnail, err)
+	}
+	items, _ := r.Items()
+	contents, err := items[0].MediaContents()
+	if err != nil || len(contents) != 2 {
+		t.Fatalf("contents = %+v, %v", contents, err)
+	}
+	want := []MediaContent{
+		{URL: "http://v/1-360.mp4", FileSize: 9007199254740993, Type: "video/mp4", Medium: "video", Expression: "full", Bitrate: 800, Framerate: 29.97,
+			Duration: 185 * time.Second, Height: 360, Width: 640, Lang: "en"},
+		{URL: "http://v/1-720.mp4", Type: "video/mp4", Medium: "video", IsDefault: true, Expression: "sample", SamplingRate: 44.1, Channels: 2,
+			Height: 720, Width: 1280},
+	}
+	for c := range contents {
+		got := contents[c]
+		got.MediaDetails = MediaDetails{}
+		if !reflect.DeepEqual(got, want[c]) {
+			t.Errorf("content %d = %+v, want %+v", c, got, want[c])
+		}
+	}
+	if content, err := items[0].MediaContent(); err != nil || content.URL != "http://v/1-720.mp4" {
+		t.Errorf("default content = %+v, %v", content, err)
+	}
+	if content, err := items[1].MediaContent(); err != nil || content.URL != "http://v/2.mp3" {
+		t.Errorf("first content without a default = %+v, %v", content, err)
+	}
+	if thumbnail, err := items[0].MediaThumbnail(); err != nil || thumbnail.url != "http://v/1.jpg" || thumbnail.height != 720 {
+		t.Errorf("item thumbnail = %+v, %v", thumbnail, err)
+	}
+	if _, err := items[2].MediaContents(); !errors.Is(err, ErrNotMRSS) {
+		t.Errorf("item without MediaRSS error = %v, want ErrNotMRSS", err)
+	}
+}
+
+func TestMRSSContent(t *testing.T) {
+	checkAllPaths(t, mrssFeed, checkMRSS)
+}
+
+//Numbers that can't be read are left at 0 and an unknown expression is kept as written
+func TestMRSSContentMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:content url="http://v/1.mp4" fileSize="-1" bitrate="fast" framerate="" duration="long" channels="stereo" height="1e3"
+isDefault="1" expression="Preview"/><media:thumbnail url="http://v/1.jpg" width="wide"/></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		contents, err := items[0].MediaContents()
+		if err != nil || len(contents) != 1 {
+			t.Fatalf("contents = %+v, %v", contents, err)
+		}
+		got := contents[0]
+		got.MediaDetails = MediaDetails{}
+		if want := (MediaContent{URL: "http://v/1.mp4", Expression: "preview"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("content = %+v, want %+v", got, want)
+		}
+		if content, err := items[0].MediaContent(); err != nil || content.URL != "http://v/1.mp4" {
+			t.Errorf("only content = %+v, %v", content, err)
+		}
+		thumbnail, err := items[0].MediaThumbnail()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := thumbnail.Width(); !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("thumbnail width error = %v", err)
+		}
+	})
+}
+
+const groupFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:credit role="producer">Item Prod</media:credit>
+<media:group><media:title>Group title</media:title><media:description>Group desc</media:description><media:thumbnail url="http://v/g.jpg" width="480" height="360"/>
+<media:content url="http://v/240.mp4" type="video/mp4" medium="video" height="240" bitrate="300"/>
+<media:content url="http://v/720.mp4" type="video/mp4" medium="video" height="720" bitrate="2000" isDefault="true"><media:title>HD</media:title></media:content>
+<media:content url="http://v/1080.webm" type="video/webm" medium="video" height="1080" bitrate="4000"/>
+<media:content url="http://v/a.m4a" type="audio/mp4" medium="audio" bitrate="128"/>
+</media:group></item></channel></rss>`
+
+func checkGroups(t *testing.T, r *RSS) {
+	t.Helper()
+	items, _ := r.Items()
+	item := &items[0]
+	groups, err := item.MediaGroups()
+	if err != nil || len(groups) != 1 || len(groups[0].Contents) != 4 {
+		t.Fatalf("groups = %+v, %v", groups, err)
+	}
+	if groups[0].Title != "Group title" || groups[0].Contents[0].Title != "" { //Inheritance happens in Renditions, not while parsing
+		t.Errorf("group title = %q, first content title = %q", groups[0].Title, groups[0].Contents[0].Title)
+	}
+	renditions := groups[0].Renditions()
+	if renditions[0].Title != "Group title" || renditions[0].Description != "Group desc" || renditions[0].Thumbnails[0].url != "http://v/g.jpg" {
+		t.Errorf("rendition 0 details = %+v", renditions[0].MediaDetails)
+	}
+	if renditions[1].Title != "HD" || renditions[1].Description != "Group desc" {
+		t.Errorf("rendition 1 title = %q, description = %q", renditions[1].Title, renditions[1].Description)
+	}
+	all, err := item.MediaRenditions()
+	if err != nil || len(all) != 4 {
+		t.Fatalf("item renditions = %d, %v", len(all), err)
+	}
+	if credits := all[0].CreditsFor("producer"); len(credits) != 1 || credits[0].Name != "Item Prod" {
+		t.Errorf("credits inherited from the item = %+v", credits)
+	}
+
+	tests := []struct {
+		name        string
+		constraints MediaConstraints
+		want        string
+	}{
+		{"highest video", MediaConstraints{Medium: "video"}, "http://v/1080.webm"},
+		{"height limit", MediaConstraints{MaxHeight: 720, Medium: "video"}, "http://v/720.mp4"},
+		{"type preference", MediaConstraints{Types: []string{"video/webm", "video/mp4"}, MaxBitrate: 2500}, "http://v/720.mp4"},
+		{"type over height", MediaConstraints{Types: []string{"video/mp4", "video/webm"}}, "http://v/720.mp4"},
+		{"audio", MediaConstraints{Medium: "Audio"}, "http://v/a.m4a"},
+		{"smallest over limits", MediaConstraints{Medium: "video", MaxHeight: 100}, "http://v/240.mp4"},
+	}
+	for _, tt := range tests {
+		if got, err := item.BestRendition(tt.constraints); err != nil || got.URL != tt.want {
+			t.Errorf("BestRendition(%s) = %+v, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+	if got, err := groups[0].BestRendition(MediaConstraints{MaxBitrate: 500, Medium: "video"}); err != nil || got.URL != "http://v/240.mp4" {
+		t.Errorf("group BestRendition = %+v, %v", got, err)
+	}
+	if _, err := item.BestRendition(MediaConstraints{Types: []string{"image/png"}}); !errors.Is(err, ErrNoRendition) {
+		t.Errorf("unacceptable type error = %v, want ErrNoRendition", err)
+	}
+}
+
+func TestMediaGroup(t *testing.T) {
+	checkAllPaths(t, groupFeed, checkGroups)
+}
+
+//A group without content objects gives no renditions, and numbers that can't be read leave a rendition within every limit
+func TestMediaGroupMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:title>T</media:title><media:group><media:title>Empty</media:title></media:group></item>
+<item><title>2</title><media:group><media:content url="http://v/x.mp4" medium="video" height="tall" bitrate="fast"/>
+<media:content url="http://v/1080.mp4" medium="video" height="1080" bitrate="4000"/></media:group></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		var fieldErr *FieldError
+		if _, err := items[0].MediaRenditions(); !errors.As(err, &fieldErr) || fieldErr.Field != "content" {
+			t.Errorf("renditions of an empty group error = %v", err)
+		}
+		if _, err := items[0].BestRendition(MediaConstraints{}); !errors.As(err, &fieldErr) {
+			t.Errorf("BestRendition of an empty group error = %v", err)
+		}
+		if got, err := items[1].BestRendition(MediaConstraints{MaxHeight: 720}); err != nil || got.URL != "http://v/x.mp4" || got.Height != 0 || got.Bitrate != 0 {
+			t.Errorf("BestRendition = %+v, %v", got, err)
+		}
+	})
+}
+
+const mediaDetailsFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title>
+<media:title type="html">&lt;b&gt;Hi&lt;/b&gt;</media:title><media:description>Plain</media:description>
+<media:keywords>kitty, cat, big dog, yarn</media:keywords>
+<media:thumbnail url="http://v/a.jpg" width="75" height="50" time="12:05:01.123"/>
+<media:thumbnail url="http://v/b.jpg" time="30"/>
+<media:player url="http://v/player?id=1" width="400" height="200"/>
+<media:embed url="http://v/e.swf" width="512" height="323"><media:param name="type">application/x-shockwave-flash</media:param><media:param name="allowFullScreen">true</media:param></media:embed>
+<media:text type="plain" lang="en" start="00:00:03.000" end="00:00:10.000"> Oh, say, can you see</media:text>
+<media:text start="00:00:10.000">By the dawn's early light</media:text>
+<media:subTitle type="application/smil" lang="en-us" href="http://v/sub.smil"/>
+<media:group><media:content url="http://v/1.mp4" height="720"/><media:keywords>grp</media:keywords></media:group>
+</item></channel></rss>`
+
+func checkMediaDetails(t *testing.T, r *RSS) {
+	t.Helper()
+	items, _ := r.Items()
+	item := &items[0]
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"title", func() (interface{}, error) { return item.MediaTitle() }, "<b>Hi</b>"},
+		{"description", func() (interface{}, error) { return item.MediaDescription() }, "Plain"},
+		{"types", func() (interface{}, error) {
+			d, err := item.MediaDetails()
+			return [2]string{d.TitleType, d.DescriptionType}, err
+		}, [2]string{"html", "plain"}},
+		{"keywords", func() (interface{}, error) { return item.MediaKeywords() }, []string{"kitty", "cat", "big dog", "yarn"}},
+		{"thumbnails", func() (interface{}, error) { return item.MediaThumbnails() }, []MediaThumbnail{
+			{Image: Image{url: "http://v/a.jpg", width: 75, height: 50}, Time: 12*time.Hour + 5*time.Minute + time.Second + 123*time.Millisecond},
+			{Image: Image{url: "http://v/b.jpg"}, Time: 30 * time.Second}}},
+		{"first thumbnail", func() (interface{}, error) {
+			thumbnail, err := item.MediaThumbnail()
+			return thumbnail.url, err
+		}, "http://v/a.jpg"},
+		{"player", func() (interface{}, error) {
+			p, err := item.MediaPlayer()
+			return *p, err
+		}, MediaPlayer{URL: "http://v/player?id=1", Width: 400, Height: 200}},
+		{"embed", func() (interface{}, error) {
+			e, err := item.MediaEmbed()
+			return *e, err
+		}, MediaEmbed{URL: "http://v/e.swf", Width: 512, Height: 323, Params: map[string]string{"type": "application/x-shockwave-flash", "allowFullScreen": "true"}}},
+		{"texts", func() (interface{}, error) { return item.MediaTexts() }, []MediaText{
+			{Text: "Oh, say, can you see", Type: "plain", Lang: "en", Start: 3 * time.Second, End: 10 * time.Second},
+			{Text: "By the dawn's early light", Type: "plain", Start: 10 * time.Second}}},
+		{"subtitles", func() (interface{}, error) { return item.MediaSubTitles() }, []MediaSubTitle{{URL: "http://v/sub.smil", Type: "application/smil", Lang: "en-us"}}},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+	renditions, err := item.MediaRenditions()
+	if err != nil || len(renditions) != 1 {
+		t.Fatalf("renditions = %+v, %v", renditions, err)
+	}
+	if details := renditions[0].MediaDetails; !reflect.DeepEqual(details.Keywords, []string{"grp"}) || details.Player == nil || len(details.Texts) != 2 {
+		t.Errorf("rendition details = %+v", details)
+	}
+}
+
+func TestMediaDescriptive(t *testing.T) {
+	checkAllPaths(t, mediaDetailsFeed, checkMediaDetails)
+}
+
+//Thumbnails without a url and nameless params are dropped, and numbers or offsets that can't be read are left at 0
+func TestMediaDescriptiveMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:thumbnail width="10"/><media:thumbnail url="http://v/a.jpg" time="soon"/><media:player url="http://v/p" width="wide"/>
+<media:embed url="http://v/e"><media:param>nameless</media:param></media:embed><media:text start="a:b">x</media:text></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		details, err := items[0].MediaDetails()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := MediaDetails{
+			Thumbnails: []MediaThumbnail{{Image: Image{url: "http://v/a.jpg"}}},
+			Player:     &MediaPlayer{URL: "http://v/p"},
+			Embed:      &MediaEmbed{URL: "http://v/e"},
+			Texts:      []MediaText{{Text: "x", Type: "plain"}},
+		}
+		if !reflect.DeepEqual(*details, want) {
+			t.Errorf("details = %+v, want %+v", *details, want)
+		}
+		var fieldErr *FieldError
+		if _, err := items[0].MediaTitle(); !errors.As(err, &fieldErr) || fieldErr.Namespace != NamespaceMRSS || fieldErr.Field != "title" {
+			t.Errorf("missing title error = %v", err)
+		}
+	})
+}
+
+func TestMediaBuilder(t *testing.T) {
+	media := NewMedia().WithHTMLTitle("<i>x</i>").WithKeywords("a", "b").AddThumbnail(NewImage("http://t", "", "", 0, 0), 5*time.Second).
+		WithPlayer("http://p", 1, 2).AddText(MediaText{Text: "hi", Start: time.Second})
+	built, err := NewChannel("c", "http://c", "d").AddItem(NewItem("t", "http://i", "d").WithMedia(media)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := r.Items()
+	details, err := items[0].MediaDetails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MediaDetails{
+		Title:      "<i>x</i>",
+		TitleType:  "html",
+		Keywords:   []string{"a", "b"},
+		Thumbnails: []MediaThumbnail{{Image: Image{url: "http://t"}, Time: 5 * time.Second}},
+		Player:     &MediaPlayer{URL: "http://p", Width: 1, Height: 2},
+		Texts:      []MediaText{{Text: "hi", Type: "plain", Start: time.Second}},
+	}
+	if !reflect.DeepEqual(*details, want) {
+		t.Errorf("built details = %+v, want %+v\n%s", *details, want, out)
+	}
+}
+
+const rightsFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<media:restriction relationship="allow" type="country">us ca</media:restriction><media:keywords>news,sports , weather,,</media:keywords>
+<item><title>1</title>
+<media:rating scheme="urn:mpaa">PG-13</media:rating><media:rating>nonadult</media:rating>
+<media:restriction relationship="deny" type="country">fr</media:restriction>
+<media:restriction relationship="allow" type="uri">http://tv.example</media:restriction>
+<media:restriction relationship="deny" type="sharing"/>
+<media:license type="text/html" href="http://cc">CC BY</media:license>
+<media:copyright url="http://terms">2005 Foo</media:copyright>
+<media:status state="active"/>
+<media:price type="rent" price="19.99" currency="eur"/>
+<media:group><media:content url="http://v/1.mp4"/><media:status state="blocked" reason="http://why"/></media:group>
+</item>
+<item><title>2</title><media:content url="http://v/2.mp4"/></item>
+<item><title>3</title><media:status state="deleted"/></item>
+</channel></rss>`
+
+func checkRights(t *testing.T, r *RSS) {
+	t.Helper()
+	if keywords, err := r.Keywords(); err != nil || !reflect.DeepEqual(keywords, []string{"news", "sports", "weather"}) {
+		t.Errorf("channel keywords = %q, %v", keywords, err)
+	}
+	if restrictions, err := r.MRSSRestrictions(); err != nil || !reflect.DeepEqual(restrictions, []MediaRestriction{{Relationship: "allow", Type: "country", Values: []string{"us", "ca"}}}) {
+		t.Errorf("channel restrictions = %+v, %v", restrictions, err)
+	}
+	items, _ := r.Items()
+	item := &items[0]
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"ratings", func() (interface{}, error) { return item.MediaRatings() }, []MediaRating{{Scheme: "urn:mpaa", Value: "pg-13"}, {Scheme: "urn:simple", Value: "nonadult"}}},
+		{"license", func() (interface{}, error) {
+			l, err := item.MediaLicense()
+			return *l, err
+		}, MediaLicense{Name: "CC BY", Type: "text/html", Href: "http://cc"}},
+		{"copyright", func() (interface{}, error) {
+			c, err := item.MediaCopyright()
+			return *c, err
+		}, MediaCopyright{Text: "2005 Foo", URL: "http://terms"}},
+		{"status", func() (interface{}, error) {
+			s, err := item.MediaStatus()
+			return s.State, err
+		}, "active"},
+		{"prices", func() (interface{}, error) {
+			p, err := item.MediaPrices()
+			return [2]interface{}{p[0].Price, p[0].Currency}, err
+		}, [2]interface{}{19.99, "EUR"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+
+	playable := []struct {
+		name              string
+		item              *Item
+		country, platform string
+		want              bool
+	}{
+		{"allowed uri", item, "de", "http://tv.example", true},
+		{"denied country", item, "fr", "", false},
+		{"other uri", item, "", "http://other", false},
+		{"channel allows", &items[1], "US", "", true},
+		{"channel denies", &items[1], "de", "", false},
+		{"no checks", &items[1], "", "", true},
+		{"deleted", &items[2], "us", "", false},
+	}
+	for _, tt := range playable {
+		if got := r.MediaPlayable(tt.item, tt.country, tt.platform); got != tt.want {
+			t.Errorf("MediaPlayable(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if details, _ := item.MediaDetails(); details.Shareable() {
+		t.Error("item denying sharing is shareable")
+	}
+	renditions, _ := item.MediaRenditions()
+	if renditions[0].Playable("", "") || renditions[0].Status.Reason != "http://why" {
+		t.Errorf("blocked rendition status = %+v", renditions[0].Status)
+	}
+}
+
+func TestMediaRights(t *testing.T) {
+	checkAllPaths(t, rightsFeed, checkRights)
+}
+
+//Attributes are compared case-insensitively, a price that can't be read is 0 and an unknown status doesn't block playback
+func TestMediaRightsMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:restriction relationship="ALLOW" type="Country">US</media:restriction><media:restriction type="sharing"/>
+<media:price price="free" currency="usd"/><media:status state="Pending"/></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		item := &items[0]
+		if prices, err := item.MediaPrices(); err != nil || !reflect.DeepEqual(prices, []MediaPrice{{Currency: "USD"}}) {
+			t.Errorf("prices = %+v, %v", prices, err)
+		}
+		if !r.MediaPlayable(item, "us", "") || r.MediaPlayable(item, "de", "") {
+			t.Error("country restriction not applied case-insensitively")
+		}
+		if details, _ := item.MediaDetails(); !details.Shareable() {
+			t.Error("sharing restriction without a relationship denies sharing")
+		}
+		var fieldErr *FieldError
+		if _, err := item.MediaLicense(); !errors.As(err, &fieldErr) || fieldErr.Field != "license" {
+			t.Errorf("missing license error = %v", err)
+		}
+	})
+}
+
+func TestMediaVerifyWithoutHash(t *testing.T) {
+	var fieldErr *FieldError
+	err := (&MediaContent{URL: "http://v/1.mp4"}).Verify([]byte("x"))
+	if !errors.As(err, &fieldErr) || fieldErr.Element != ElementMediaContent || fieldErr.Namespace != NamespaceMRSS || fieldErr.Field != "hash" {
+		t.Errorf("error = %v, want a media:hash FieldError on the content object", err)
+	}
+}
+
+const communityFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/" xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title>
+<media:community><media:starRating average="3.5" count="20" min="1" max="10"/><media:statistics views="5" favorites="7"/><media:tags>news: 5, abc:3, reuters</media:tags></media:community>
+<media:peerLink type="application/x-bittorrent" href="http://v/1.torrent"/>
+<media:location description="My house" start="00:01" end="01:00"><georss:where><gml:Point><gml:pos>35.669998 139.770004</gml:pos></gml:Point></georss:where></media:location>
+<media:location description="Elsewhere"/>
+<media:location description="Nowhere"><georss:point>95 10</georss:point></media:location>
+<media:scenes><media:scene><media:sceneTitle>sceneTitle1</media:sceneTitle><media:sceneDescription>d1</media:sceneDescription><media:sceneStartTime>00:15</media:sceneStartTime><media:sceneEndTime>00:45</media:sceneEndTime></media:scene><media:scene><media:sceneTitle>t2</media:sceneTitle><media:sceneStartTime>00:45</media:sceneStartTime></media:scene></media:scenes>
+<media:content url="http://v/1.mp4"><media:hash algo="md5">5D41402ABC4B2A76B9719D911017C592</media:hash><media:hash algo="sha-1">aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d</media:hash></media:content>
+<media:content url="http://v/2.mp4"><media:hash algo="sha-256">00</media:hash></media:content>
+</item></channel></rss>`
+
+func checkCommunity(t *testing.T, r *RSS) {
+	t.Helper()
+	items, _ := r.Items()
+	item := &items[0]
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"community", func() (interface{}, error) {
+			c, err := item.MediaCommunity()
+			return *c, err
+		}, MediaCommunity{StarAverage: 3.5, StarCount: 20, StarMin: 1, StarMax: 10, Views: 5, Favorites: 7,
+			Tags: []MediaTag{{Name: "news", Weight: 5}, {Name: "abc", Weight: 3}, {Name: "reuters", Weight: 1}}}},
+		{"peer links", func() (interface{}, error) { return item.MediaPeerLinks() }, []MediaPeerLink{{Type: "application/x-bittorrent", URL: "http://v/1.torrent"}}},
+		{"locations", func() (interface{}, error) { return item.MediaLocations() }, []MediaLocation{
+			{Description: "My house", Start: time.Second, End: time.Minute, Point: &GeoPoint{Lat: 35.669998, Lon: 139.770004}},
+			{Description: "Elsewhere"},
+			{Description: "Nowhere"}, //Latitude out of range
+		}},
+		{"scenes", func() (interface{}, error) { return item.MediaScenes() }, []MediaScene{
+			{Title: "sceneTitle1", Description: "d1", Start: 15 * time.Second, End: 45 * time.Second},
+			{Title: "t2", Start: 45 * time.Second}}},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+
+	contents, _ := item.MediaContents()
+	verify := []struct {
+		name    string
+		content *MediaContent
+		data    string
+		want    error
+	}{
+		{"md5 and sha-1", &contents[0], "hello", nil},
+		{"mismatch", &contents[0], "hellO", ErrIntegrityMismatch},
+		{"unsupported algorithm", &contents[1], "x", ErrUnsupportedIntegrity},
+		{"no hash", &MediaContent{}, "", ErrFieldNotPopulated},
+	}
+	for _, tt := range verify {
+		if err := tt.content.Verify([]byte(tt.data)); !errors.Is(err, tt.want) || tt.want == nil && err != nil {
+			t.Errorf("Verify(%s) = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMediaCommunity(t *testing.T) {
+	checkAllPaths(t, communityFeed, checkCommunity)
+}
+
+//Numbers, positions and offsets that can't be read are left at their zero value, and a tag whose weight isn't a number keeps its
+//colon
+func TestMediaCommunityMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/" xmlns:georss="http://www.georss.org/georss"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:community><media:starRating average="high" count="many"/><media:statistics views="-5"/><media:tags>a:b, , :3</media:tags></media:community>
+<media:location description="Somewhere"><georss:point>north east</georss:point></media:location>
+<media:scenes><media:scene><media:sceneTitle>s</media:sceneTitle><media:sceneStartTime>later</media:sceneStartTime></media:scene></media:scenes>
+<media:content url="http://v/1.mp4"><media:hash>not hex</media:hash></media:content></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		item := &items[0]
+		if c, err := item.MediaCommunity(); err != nil || !reflect.DeepEqual(*c, MediaCommunity{Tags: []MediaTag{{Name: "a:b", Weight: 1}}}) {
+			t.Errorf("community = %+v, %v", c, err)
+		}
+		if locations, err := item.MediaLocations(); err != nil || !reflect.DeepEqual(locations, []MediaLocation{{Description: "Somewhere"}}) {
+			t.Errorf("locations = %+v, %v", locations, err)
+		}
+		if scenes, err := item.MediaScenes(); err != nil || !reflect.DeepEqual(scenes, []MediaScene{{Title: "s"}}) {
+			t.Errorf("scenes = %+v, %v", scenes, err)
+		}
+		contents, _ := item.MediaContents()
+		if err := contents[0].Verify([]byte("hello")); !errors.Is(err, ErrIntegrityMismatch) {
+			t.Errorf("Verify against a hash that isn't hex = %v, want ErrIntegrityMismatch", err)
+		}
+	})
+}
+
+const creditFeed = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title>
+<media:credit role="actor">Ann</media:credit><media:credit role="Actor">Bob</media:credit><media:credit>Nobody</media:credit>
+<media:credit role="owner" scheme="urn:yvs">Chan</media:credit>
+<media:group><media:credit role="director">Dee</media:credit><media:content url="http://v/1.mp4"><media:credit role="editor">Ed</media:credit></media:content></media:group>
+</item></channel></rss>`
+
+func checkCredits(t *testing.T, r *RSS) {
+	t.Helper()
+	items, _ := r.Items()
+	item := &items[0]
+	want := []MediaCredit{
+		{Role: "actor", Scheme: "urn:ebu", Name: "Ann"},
+		{Role: "actor", Scheme: "urn:ebu", Name: "Bob"},
+		{Scheme: "urn:ebu", Name: "Nobody"},
+		{Role: "owner", Scheme: "urn:yvs", Name: "Chan"},
+	}
+	if credits, err := item.MediaCredits(); err != nil || !reflect.DeepEqual(credits, want) {
+		t.Errorf("credits = %+v, %v, want %+v", credits, err, want)
+	}
+	roles := []struct {
+		role string
+		want []string
+	}{
+		{"ACTOR", []string{"Ann", "Bob"}},
+		{"", []string{"Nobody"}},
+		{"owner", []string{"Chan"}},
+		{"grip", nil},
+	}
+	for _, tt := range roles {
+		credits, err := item.MediaCreditsFor(tt.role)
+		var names []string
+		for _, credit := range credits {
+			names = append(names, credit.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) || tt.want == nil && !errors.Is(err, ErrFieldNotPopulated) {
+			t.Errorf("MediaCreditsFor(%q) = %v, %v, want %v", tt.role, names, err, tt.want)
+		}
+	}
+	groups, _ := item.MediaGroups()
+	if credits := groups[0].CreditsFor("director"); len(credits) != 1 || credits[0].Name != "Dee" {
+		t.Errorf("group credits = %+v", credits)
+	}
+	if credits := groups[0].Contents[0].CreditsFor("editor"); len(credits) != 1 || credits[0].Name != "Ed" {
+		t.Errorf("content credits = %+v", credits)
+	}
+}
+
+func TestMediaCredits(t *testing.T) {
+	checkAllPaths(t, creditFeed, checkCredits)
+}
+
+//Credits without a name are dropped, and roles and schemes are trimmed
+func TestMediaCreditsMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><title>V</title><link>http://v</link><description>D</description>
+<item><title>1</title><media:credit role=" Actor " scheme=" ">  Ann  </media:credit><media:credit role="actor"> </media:credit></item>
+<item><title>2</title><media:title>T</media:title><media:credit role="actor"/></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		items, _ := r.Items()
+		if credits, err := items[0].MediaCredits(); err != nil || !reflect.DeepEqual(credits, []MediaCredit{{Role: "actor", Scheme: "urn:ebu", Name: "Ann"}}) {
+			t.Errorf("credits = %+v, %v", credits, err)
+		}
+		var fieldErr *FieldError
+		if _, err := items[1].MediaCredits(); !errors.As(err, &fieldErr) || fieldErr.Field != "credit" {
+			t.Errorf("nameless credits error = %v", err)
+		}
+	})
+}
+
+func TestMediaCreditBuilder(t *testing.T) {
+	media := NewMedia().AddCredit("actor", "A").AddCredit("actor", "B").AddSchemeCredit("urn:yvs", "owner", "O")
+	built, err := NewChannel("c", "http://c", "d").AddItem(NewItem("t", "http://i", "d").WithMedia(media)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := r.Items()
+	want := []MediaCredit{{Role: "actor", Scheme: "urn:ebu", Name: "A"}, {Role: "actor", Scheme: "urn:ebu", Name: "B"}, {Role: "owner", Scheme: "urn:yvs", Name: "O"}}
+	if credits, err := items[0].MediaCredits(); err != nil || !reflect.DeepEqual(credits, want) {
+		t.Errorf("built credits = %+v, %v\n%s", credits, err, out)
+	}
+}
@@ -0,0 +1,283 @@
+package easyrss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Podcasting 2.0 (https://podcastindex.org/namespace/1.0) fields, shared between channels and items like ItunesMeta
+type PodcastMeta struct {
+	funding             []Funding
+	persons             []Person
+	location            *Location
+	license             *License
+	locked              *Locked   //Channel only
+	guid                string    //Channel only
+	medium              string    //Channel only
+	trailers            []Trailer //Channel only
+	values              []Value
+	transcripts         []Transcript         //Item only
+	chapters            *ChaptersLink        //Item only
+	contentLinks        []ContentLink        //Item only
+	alternateEnclosures []AlternateEnclosure //Item only
+}
+
+//A <podcast:funding> link where listeners can support the show
+type Funding struct {
+	URL  string
+	Text string //Call to action, e.g. "Support the show!"
+}
+
+//A person involved with the podcast or episode, from <podcast:person>
+type Person struct {
+	Name  string
+	Role  string //Defaults to "host"
+	Group string //Defaults to "cast"
+	Image string //Picture of the person
+	Href  string //Page about the person
+}
+
+//What a podcast or episode is about or where it was recorded, from <podcast:location>
+type Location struct {
+	Name string
+	Geo  string //geo: URI, e.g. "geo:30.2672,97.7431"
+	OSM  string //OpenStreetMap identifier, e.g. "R113314"
+}
+
+//Content license from <podcast:license>
+type License struct {
+	Identifier string //SPDX identifier, e.g. "cc-by-4.0", or a custom name
+	URL        string //Required when the identifier isn't in the SPDX list
+}
+
+//Whether other platforms may import the feed, from <podcast:locked>
+type Locked struct {
+	Locked bool
+	Owner  string //Email address that can unlock the feed
+}
+
+//A channel-level trailer from <podcast:trailer>
+type Trailer struct {
+	Title   string
+	URL     string
+	PubDate *time.Time
+	Length  uint64 //Size in bytes
+	Type    string //MIME type
+	Season  int    //Season the trailer is for, 0 if it applies to the whole podcast
+}
+
+//A Podcasting 2.0 <podcast:transcript> link. Fetch URL and pass the body to Parse to get timed text.
+type Transcript struct {
+	URL      string
+	Type     string //MIME type, e.g. "text/vtt", "application/x-subrip" or "application/json"
+	Language string //Defaults to the channel language when empty
+	Rel      string //"captions" when the file is meant to be shown as closed captions
+}
+
+//A Podcasting 2.0 <podcast:chapters> link. Fetch URL and pass the body to ParseChapters to get the chapter markers.
+type ChaptersLink struct {
+	URL  string
+	Type string //MIME type, normally "application/json+chapters"
+}
+
+//Sets Appropriate Field Given Podcasting 2.0 Node
+func setPodcastMetaField(n node, p *PodcastMeta) {
+	tagContent := strings.TrimSpace(n.Content())
+	switch n.Name() {
+	case "funding":
+		p.funding = append(p.funding, Funding{URL: n.Attr("url"), Text: tagContent})
+	case "person":
+		person := Person{Name: tagContent, Role: strings.ToLower(n.Attr("role")), Group: strings.ToLower(n.Attr("group")), Image: n.Attr("img"), Href: n.Attr("href")}
+		if person.Role == "" {
+			person.Role = "host"
+		}
+		if person.Group == "" {
+			person.Group = "cast"
+		}
+		p.persons = append(p.persons, person)
+	case "location":
+		p.location = &Location{Name: tagContent, Geo: n.Attr("geo"), OSM: n.Attr("osm")}
+	case "license":
+		p.license = &License{Identifier: tagContent, URL: n.Attr("url")}
+	case "locked":
+		p.locked = &Locked{Locked: strings.EqualFold(tagContent, "yes"), Owner: n.Attr("owner")}
+	case "guid":
+		p.guid = tagContent
+	case "medium":
+		p.medium = strings.ToLower(tagContent)
+	case "trailer":
+		trailer := Trailer{Title: tagContent, URL: n.Attr("url"), PubDate: parseDate(n.Attr("pubdate")), Type: n.Attr("type")}
+		trailer.Length, _ = strconv.ParseUint(n.Attr("length"), 10, 64)
+		trailer.Season, _ = strconv.Atoi(n.Attr("season"))
+		p.trailers = append(p.trailers, trailer)
+	case "transcript":
+		p.transcripts = append(p.transcripts, Transcript{
+			URL:      n.Attr("url"),
+			Type:     strings.ToLower(strings.TrimSpace(n.Attr("type"))),
+			Language: n.Attr("language"),
+			Rel:      n.Attr("rel"),
+		})
+	case "chapters":
+		p.chapters = &ChaptersLink{URL: n.Attr("url"), Type: strings.ToLower(strings.TrimSpace(n.Attr("type")))}
+	case "value":
+		p.values = append(p.values, parseValue(n))
+	case "alternateEnclosure":
+		p.alternateEnclosures = append(p.alternateEnclosures, parseAlternateEnclosure(n))
+	case "contentLink":
+		p.contentLinks = append(p.contentLinks, ContentLink{Href: n.Attr("href"), Text: tagContent})
+	}
+}
+
+//Parses a downloaded transcript according to its Type.
+func (t Transcript) Parse(data []byte) ([]Cue, error) {
+	return ParseTranscript(data, t.Type)
+}
+
+//Whether or not this feed implements Podcasting 2.0 Extensions
+func (r *RSS) IsPodcasting20() bool {
+	return r.channel.isPodcast
+}
+
+//Returns the channel's Podcasting 2.0 funding links. If the channel doesn't contain Podcasting 2.0 Extensions or has no "funding" tags, will return nil and an error.
+func (r *RSS) PodcastFunding() ([]Funding, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(r.channel.podcast.funding) == 0 {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "funding")
+	}
+	return r.channel.podcast.funding, nil
+}
+
+//Returns the people involved with the podcast. If the channel doesn't contain Podcasting 2.0 Extensions or has no "person" tags, will return nil and an error.
+func (r *RSS) PodcastPersons() ([]Person, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(r.channel.podcast.persons) == 0 {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "person")
+	}
+	return r.channel.podcast.persons, nil
+}
+
+//Returns the podcast location. If the channel doesn't contain Podcasting 2.0 Extensions or hasn't populated the "location" field, will return nil and an error.
+func (r *RSS) PodcastLocation() (*Location, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if r.channel.podcast.location == nil {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "location")
+	}
+	return r.channel.podcast.location, nil
+}
+
+//Returns the podcast license. If the channel doesn't contain Podcasting 2.0 Extensions or hasn't populated the "license" field, will return nil and an error.
+func (r *RSS) PodcastLicense() (*License, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if r.channel.podcast.license == nil {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "license")
+	}
+	return r.channel.podcast.license, nil
+}
+
+//Returns whether the feed may be imported by other platforms. If the channel doesn't contain Podcasting 2.0 Extensions or hasn't populated the "locked" field, will return nil and an error.
+func (r *RSS) PodcastLocked() (*Locked, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if r.channel.podcast.locked == nil {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "locked")
+	}
+	return r.channel.podcast.locked, nil
+}
+
+//Returns the podcast's globally unique identifier. If the channel doesn't contain Podcasting 2.0 Extensions or hasn't populated the "guid" field, will return an empty string and an error.
+func (r *RSS) PodcastGUID() (string, error) {
+	if !r.channel.isPodcast {
+		return "", ErrNotPodcast
+	}
+	if r.channel.podcast.guid == "" {
+		return "", notPopulated(ElementChannel, NamespacePodcast, "guid")
+	}
+	return r.channel.podcast.guid, nil
+}
+
+//Returns the podcast medium, e.g. "podcast", "music" or "audiobook". If the channel doesn't contain Podcasting 2.0 Extensions or hasn't populated the "medium" field, will return an empty string and an error.
+func (r *RSS) PodcastMedium() (string, error) {
+	if !r.channel.isPodcast {
+		return "", ErrNotPodcast
+	}
+	if r.channel.podcast.medium == "" {
+		return "", notPopulated(ElementChannel, NamespacePodcast, "medium")
+	}
+	return r.channel.podcast.medium, nil
+}
+
+//Returns the podcast trailers. If the channel doesn't contain Podcasting 2.0 Extensions or has no "trailer" tags, will return nil and an error.
+func (r *RSS) PodcastTrailers() ([]Trailer, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(r.channel.podcast.trailers) == 0 {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "trailer")
+	}
+	return r.channel.podcast.trailers, nil
+}
+
+//Returns the people involved with the episode. If the item doesn't contain Podcasting 2.0 Extensions or has no "person" tags, will return nil and an error.
+func (i *Item) PodcastPersons() ([]Person, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(i.podcast.persons) == 0 {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "person")
+	}
+	return i.podcast.persons, nil
+}
+
+//Returns the episode location. If the item doesn't contain Podcasting 2.0 Extensions or hasn't populated the "location" field, will return nil and an error.
+func (i *Item) PodcastLocation() (*Location, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if i.podcast.location == nil {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "location")
+	}
+	return i.podcast.location, nil
+}
+
+//Returns the episode license. If the item doesn't contain Podcasting 2.0 Extensions or hasn't populated the "license" field, will return nil and an error.
+func (i *Item) PodcastLicense() (*License, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if i.podcast.license == nil {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "license")
+	}
+	return i.podcast.license, nil
+}
+
+//Returns the item's Podcasting 2.0 transcripts. If the item doesn't contain Podcasting 2.0 Extensions or has no "transcript" tags, will return nil and an error.
+func (i *Item) Transcripts() ([]Transcript, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(i.podcast.transcripts) == 0 {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "transcript")
+	}
+	return i.podcast.transcripts, nil
+}
+
+//Returns the item's Podcasting 2.0 chapters link. If the item doesn't contain Podcasting 2.0 Extensions or hasn't populated the "chapters" field, will return nil and an error.
+func (i *Item) Chapters() (*ChaptersLink, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if i.podcast.chapters == nil {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "chapters")
+	}
+	return i.podcast.chapters, nil
+}
@@ -0,0 +1,149 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const podcastFeed = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<podcast:locked owner="o@p">yes</podcast:locked><podcast:funding url="http://f">Support!</podcast:funding><podcast:guid>abc</podcast:guid><podcast:medium>Music</podcast:medium>
+<podcast:person role="Guest" img="http://i" href="http://h">Ann</podcast:person><podcast:person>Bob</podcast:person>
+<podcast:location geo="geo:1,2" osm="R1">Austin</podcast:location><podcast:license url="http://l">my-license</podcast:license>
+<podcast:trailer pubdate="Thu, 01 Apr 2021 08:00:00 -0500" url="http://t.mp3" length="12345" type="audio/mpeg" season="2">Trailer</podcast:trailer>
+<item><title>1</title><podcast:person role="host">Cat</podcast:person><podcast:location geo="geo:3,4">Studio</podcast:location><podcast:license>cc-by-4.0</podcast:license></item>
+<item><title>2</title></item></channel></rss>`
+
+func checkPodcast(t *testing.T, r *RSS) {
+	t.Helper()
+	if !r.IsPodcasting20() {
+		t.Fatal("IsPodcasting20 is false")
+	}
+	items, err := r.Items()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("items = %d, %v", len(items), err)
+	}
+	trailerDate := time.Date(2021, 4, 1, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+	}{
+		{"locked", func() (interface{}, error) {
+			l, err := r.PodcastLocked()
+			return *l, err
+		}, Locked{Locked: true, Owner: "o@p"}},
+		{"funding", func() (interface{}, error) { return r.PodcastFunding() }, []Funding{{URL: "http://f", Text: "Support!"}}},
+		{"guid", func() (interface{}, error) { return r.PodcastGUID() }, "abc"},
+		{"medium", func() (interface{}, error) { return r.PodcastMedium() }, "music"},
+		{"persons", func() (interface{}, error) { return r.PodcastPersons() }, []Person{
+			{Name: "Ann", Role: "guest", Group: "cast", Image: "http://i", Href: "http://h"}, {Name: "Bob", Role: "host", Group: "cast"}}},
+		{"location", func() (interface{}, error) {
+			l, err := r.PodcastLocation()
+			return *l, err
+		}, Location{Name: "Austin", Geo: "geo:1,2", OSM: "R1"}},
+		{"license", func() (interface{}, error) {
+			l, err := r.PodcastLicense()
+			return *l, err
+		}, License{Identifier: "my-license", URL: "http://l"}},
+		{"trailer", func() (interface{}, error) {
+			trailers, err := r.PodcastTrailers()
+			if len(trailers) != 1 || trailers[0].PubDate == nil || !trailers[0].PubDate.Equal(trailerDate) {
+				return trailers, err
+			}
+			trailers[0].PubDate = nil
+			return trailers, err
+		}, []Trailer{{Title: "Trailer", URL: "http://t.mp3", Length: 12345, Type: "audio/mpeg", Season: 2}}},
+		{"item persons", func() (interface{}, error) { return items[0].PodcastPersons() }, []Person{{Name: "Cat", Role: "host", Group: "cast"}}},
+		{"item location", func() (interface{}, error) {
+			l, err := items[0].PodcastLocation()
+			return *l, err
+		}, Location{Name: "Studio", Geo: "geo:3,4"}},
+		{"item license", func() (interface{}, error) {
+			l, err := items[0].PodcastLicense()
+			return *l, err
+		}, License{Identifier: "cc-by-4.0"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := items[1].PodcastPersons(); !errors.Is(err, ErrNotPodcast) {
+		t.Errorf("item without podcast tags error = %v, want ErrNotPodcast", err)
+	}
+}
+
+func TestPodcastMeta(t *testing.T) {
+	checkAllPaths(t, podcastFeed, checkPodcast)
+}
+
+//Attributes that can't be read are left at their zero value, and fields the channel lacks are reported as missing
+func TestPodcastMetaMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<podcast:locked>maybe</podcast:locked><podcast:trailer pubdate="whenever" url="http://t.mp3" length="big" season="two">T</podcast:trailer>
+<item><title>1</title></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		if l, err := r.PodcastLocked(); err != nil || *l != (Locked{}) {
+			t.Errorf("locked = %+v, %v", l, err)
+		}
+		trailers, err := r.PodcastTrailers()
+		if want := []Trailer{{Title: "T", URL: "http://t.mp3"}}; err != nil || !reflect.DeepEqual(trailers, want) {
+			t.Errorf("trailers = %+v, %v, want %+v", trailers, err, want)
+		}
+		missing := map[string]func() error{
+			"location": func() error { _, err := r.PodcastLocation(); return err },
+			"license":  func() error { _, err := r.PodcastLicense(); return err },
+			"guid":     func() error { _, err := r.PodcastGUID(); return err },
+		}
+		for name, get := range missing {
+			var fieldErr *FieldError
+			if err := get(); !errors.As(err, &fieldErr) || fieldErr.Namespace != NamespacePodcast || fieldErr.Field != name {
+				t.Errorf("%s error = %v", name, err)
+			}
+		}
+	})
+}
+
+func TestNotPodcasting20(t *testing.T) {
+	r, err := Decode([]byte(encodePlainFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.IsPodcasting20() {
+		t.Error("plain feed detected as Podcasting 2.0")
+	}
+	if _, err := r.PodcastGUID(); !errors.Is(err, ErrNotPodcast) {
+		t.Errorf("PodcastGUID error = %v, want ErrNotPodcast", err)
+	}
+}
+
+func TestPodcastBuilder(t *testing.T) {
+	r, err := NewChannel("T", "http://x", "D").WithPodcast(NewPodcast().WithGUID("g").AddFunding("http://f", "fund")).
+		AddItem(NewItem("a", "", "").WithPodcast(NewPodcast().AddTranscript("http://t", "text/vtt", "", "").WithChapters("http://c", "application/json+chapters"))).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err = Decode(out); err != nil {
+		t.Fatal(err)
+	}
+	if g, err := r.PodcastGUID(); g != "g" {
+		t.Errorf("guid = %q, %v", g, err)
+	}
+	if f, err := r.PodcastFunding(); !reflect.DeepEqual(f, []Funding{{URL: "http://f", Text: "fund"}}) {
+		t.Errorf("funding = %+v, %v", f, err)
+	}
+	items, _ := r.Items()
+	if c, err := items[0].Chapters(); err != nil || c.URL != "http://c" {
+		t.Errorf("chapters = %+v, %v", c, err)
+	}
+	if tr, err := items[0].Transcripts(); err != nil || len(tr) != 1 || tr[0].URL != "http://t" {
+		t.Errorf("transcripts = %+v, %v", tr, err)
+	}
+}
@@ -0,0 +1,69 @@
+package easyrss
+
+//Maps Dublin Core channel elements onto their RSS 2.0 equivalents, dc:creator being the managing editor and dc:publisher the
+//webmaster. Native RSS elements take precedence where both are present.
+func setDublinCoreChannelField(n node, c *Channel) {
+	tagContent := n.Content()
+	switch n.Name() {
+	case "title":
+		if c.title == "" {
+			c.title = tagContent
+		}
+	case "description":
+		if c.description == "" {
+			c.description = tagContent
+		}
+	case "language":
+		if c.language == "" {
+			c.language = tagContent
+		}
+	case "rights":
+		if c.copyright == "" {
+			c.copyright = tagContent
+		}
+	case "creator":
+		if c.editor == "" {
+			c.editor = tagContent
+		}
+	case "publisher":
+		if c.webMaster == "" {
+			c.webMaster = tagContent
+		}
+	case "date":
+		if c.pubDate == nil {
+			setLocalizedDate(&c.pubDate, &c.pubDateText, tagContent)
+		}
+	case "subject":
+		c.categories = append(c.categories, tagContent)
+	}
+}
+
+//Maps Dublin Core item elements onto their RSS 2.0 equivalents. Native RSS elements take precedence where both are present.
+func setDublinCoreItemField(n node, i *Item) {
+	tagContent := n.Content()
+	switch n.Name() {
+	case "title":
+		if i.title == "" {
+			i.title = tagContent
+		}
+	case "description":
+		if i.description == "" {
+			i.description = tagContent
+		}
+	case "creator":
+		if i.author == "" {
+			i.author = tagContent
+		}
+	case "date":
+		if i.date == nil {
+			i.setDate(tagContent)
+		}
+	case "subject":
+		i.categories = append(i.categories, Category{Value: tagContent})
+	}
+}
+
+//Whether or not this feed was decoded from an RSS 1.0 (RDF) document
+func (r *RSS) IsRDF() bool {
+	return r.channel.isRDF
+}
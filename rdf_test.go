@@ -0,0 +1,96 @@
+package easyrss
+
+import (
+	"testing"
+	"time"
+)
+
+const rdfFeed = `<?xml version="1.0" encoding="utf-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+ xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel rdf:about="http://example.org/rss">
+ <title>RDF Feed</title><link>http://example.org/</link><description>About things</description>
+ <dc:language>fr</dc:language><dc:rights>(c) Example</dc:rights><dc:creator>editor@example.org</dc:creator>
+ <dc:publisher>Example Inc</dc:publisher><dc:date>2002-09-01T12:00:00+02:00</dc:date><dc:subject>Things</dc:subject>
+ <items><rdf:Seq><rdf:li resource="http://example.org/1"/><rdf:li resource="http://example.org/2"/></rdf:Seq></items>
+</channel>
+<item rdf:about="http://example.org/1">
+ <title>First</title><link>http://example.org/1</link><description>Short</description>
+ <dc:creator>Alice</dc:creator><dc:date>2002-09-01T10:00:00Z</dc:date><dc:subject>a</dc:subject><dc:subject>b</dc:subject>
+ <content:encoded><![CDATA[<p>Long</p>]]></content:encoded>
+</item>
+<item rdf:about="http://example.org/2"><dc:title>Second</dc:title><dc:description>Only Dublin Core</dc:description></item>
+</rdf:RDF>`
+
+func TestRDFChannel(t *testing.T) {
+	r, err := Decode([]byte(rdfFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsRDF() {
+		t.Error("IsRDF() = false")
+	}
+	tests := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"title", r.Title, "RDF Feed"},
+		{"link", r.Link, "http://example.org/"},
+		{"description", r.Description, "About things"},
+		{"dc:language", r.Language, "fr"},
+		{"dc:rights", r.Copyright, "(c) Example"},
+		{"dc:creator", r.ManagingEditor, "editor@example.org"},
+		{"dc:publisher", r.WebMaster, "Example Inc"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.get(); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if d, err := r.PubDate(); err != nil || !d.Equal(time.Date(2002, 9, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("dc:date = %v, %v", d, err)
+	}
+	if c, _ := r.Categories(); len(c) != 1 || c[0] != "Things" {
+		t.Errorf("dc:subject = %v", c)
+	}
+}
+
+func TestRDFItems(t *testing.T) {
+	r, err := Decode([]byte(rdfFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := r.Items()
+	if err != nil || len(items) != 2 {
+		t.Fatal(items, err)
+	}
+	first, second := items[0], items[1]
+	tests := []struct {
+		name string
+		get  func() (string, error)
+		want string
+	}{
+		{"title", first.Title, "First"},
+		{"link", first.Link, "http://example.org/1"},
+		{"description", first.Description, "Short"},
+		{"dc:creator", first.Author, "Alice"},
+		{"content:encoded", first.Content, "<p>Long</p>"},
+		{"dc:title", second.Title, "Second"},
+		{"dc:description", second.Description, "Only Dublin Core"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.get(); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if g, _ := second.GUID(); g.Content != "http://example.org/2" || g.IsPermaLink {
+		t.Errorf("rdf:about = %+v", g)
+	}
+	if d, _ := first.Date(); !d.Equal(time.Date(2002, 9, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("dc:date = %v", d)
+	}
+	if c, _ := first.Categories(); len(c) != 2 || c[1].Value != "b" {
+		t.Errorf("dc:subject = %v", c)
+	}
+}
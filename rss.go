@@ -2,24 +2,36 @@
 package easyrss
 
 import (
-	"errors"
-	"fmt"
-	"github.com/moovweb/gokogiri"
-	"github.com/moovweb/gokogiri/xml"
 	"strconv"
+	"strings"
 	"time"
 )
 
+//Namespace URIs of the supported RSS extensions
+const (
+	itunesNS  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	mrssNS    = "http://search.yahoo.com/mrss/"
+	atomNS    = "http://www.w3.org/2005/Atom"
+	contentNS = "http://purl.org/rss/1.0/modules/content/"
+	rdfNS     = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rss1NS    = "http://purl.org/rss/1.0/"
+	dcNS      = "http://purl.org/dc/elements/1.1/"
+	podcastNS = "https://podcastindex.org/namespace/1.0"
+	georssNS  = "http://www.georss.org/georss"
+
+	podcastLegacyNS = "https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/1.0.md" //Used by early Podcasting 2.0 feeds
+)
+
 type RSS struct {
 	channel Channel
 }
 
 type xmlRSS struct {
-	channel xml.Node
+	channel node
 }
 
 type xmlChannel struct {
-	items []xml.Node
+	items []node
 }
 
 type Channel struct {
@@ -30,12 +42,34 @@ type Channel struct {
 	language    string           //Channel language
 	copyright   string           //Channel Copyright
 	categories  []string         //Channel Categories
+	image       Image            //Channel image
+	pubDate     *time.Time       //Channel publication time
+	lastBuild   *time.Time       //Last time the channel content changed
+	ttl         time.Duration    //How long the channel may be cached
+	editor      string           //Managing editor email
+	webMaster   string           //Webmaster email
+	docs        string           //URL of the format documentation
+	cloud       *Cloud           //rssCloud subscription endpoint
+	textInput   *TextInput       //Text input box
+	skipHours   HourSet          //Hours aggregators shouldn't poll
+	skipDays    DaySet           //Days aggregators shouldn't poll
+	rating      string           //PICS rating
 	items       []Item           //Slice of the items in the channel
+	liveItems   []LiveItem       //Podcasting 2.0 live streams
 	itunes      ItunesMeta       //Itunes Podcast Category
 	media       MediaChannelMeta //MediaRSS Channel Metadata
+	podcast     PodcastMeta      //Podcasting 2.0 Channel Metadata
+	atomAuthor  string           //Atom or JSON Feed author, inherited by entries without their own
+
+	pubDateText   string //Localized pubDate as written, until the channel language is known
+	lastBuildText string //Localized lastBuildDate as written, until the channel language is known
 
-	isItunes bool
-	isMRSS   bool
+	isItunes   bool
+	isMRSS     bool
+	isPodcast  bool
+	isAtom     bool
+	isRDF      bool
+	isJSONFeed bool
 }
 
 type RSSEnclosure struct {
@@ -49,45 +83,94 @@ type GUIDField struct {
 	Content     string
 }
 
+//The channel an item was republished from
+type Source struct {
+	URL   string //URL of the source channel's feed
+	Title string //Title of the source channel
+}
+
+//A category and the taxonomy it belongs to. For Atom feeds, Domain holds the category scheme.
+type Category struct {
+	Domain string
+	Value  string
+}
+
 type Item struct {
 	title       string       //Item title
 	link        string       //Item link
 	date        *time.Time   //Item publication time
+	dateText    string       //Localized date as written, until the channel language is known
 	media       MediaMeta    //MediaRSS Fields
 	description string       //Item description
+	content     string       //Full item content, from content:encoded or Atom content
+	author      string       //Item author
+	categories  []Category   //Item categories
 	enclosure   RSSEnclosure //Optional RSS Media Enclosure
 	guid        GUIDField    //Item GUID Info
+	comments    string       //URL of the item comments page
+	source      *Source      //Channel the item came from
 	itunes      ItunesMeta   //ITunes Podcast RSS Fields
+	podcast     PodcastMeta  //Podcasting 2.0 Fields
 
 	isItunes     bool //Whether item contains ITunes RSS Extensions
 	isMRSS       bool //Whether item contains MediaRSS Extensions
+	isPodcast    bool //Whether item contains Podcasting 2.0 Extensions
 	hasEnclosure bool //Whether item contains an enclosure
+	isPlainText  bool //Whether content is plain text rather than HTML, as with a JSON Feed content_text
 }
 
-//Pass in a byte slice containing the feed, get an *RSS back. You can then explore the feed easily.
+//Pass in a byte slice containing the feed, get an *RSS back. You can then explore the feed easily. RSS 1.0/2.0, Atom 1.0 and JSON Feed documents are all accepted.
 func Decode(data []byte) (*RSS, error) {
+	if isJSON(data) {
+		return DecodeJSON(data)
+	}
+	return decodeXML(defaultBackend, data)
+}
+
+//Decodes an RSS or Atom document parsed by the given backend
+func decodeXML(b backend, data []byte) (*RSS, error) {
 	rssObj := RSS{}
 	xmlrssObj := xmlRSS{} //For quick access to key nodes
 	xmlChanObj := xmlChannel{}
-	xmlDoc, err := gokogiri.ParseXml(data)
+	xmlDoc, err := b.Parse(data)
 	if err != nil {
-		fmt.Printf("%s", err.Error())
 		return nil, err
 	}
+	defer xmlDoc.Free()
 	rootNode := xmlDoc.Root()
+	if rootNode.Name() == "feed" && rootNode.Namespace() == atomNS {
+		getAtomFeed(&rssObj, rootNode)
+		return &rssObj, nil
+	}
 	getChannel(&xmlrssObj, rootNode)
+	if xmlrssObj.channel == nil {
+		return nil, ErrNotFeed
+	}
 	getChannelElem(&rssObj, xmlrssObj.channel)
 	getItems(&xmlChanObj, xmlrssObj.channel)
+	if rootNode.Name() == "RDF" && rootNode.Namespace() == rdfNS {
+		rssObj.channel.isRDF = true
+		getItems(&xmlChanObj, rootNode) //RSS 1.0 items are siblings of the channel, as are the image and text input
+		for activeElem := rootNode.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+			if name := activeElem.Name(); name == "image" || name == "textinput" {
+				setChannelField(&rssObj, activeElem)
+			}
+		}
+	}
+	rssObj.channel.resolveDates(rssObj.channel.language)
 	rssObj.channel.items = make([]Item, len(xmlChanObj.items))
 	for itemID := 0; itemID < len(xmlChanObj.items); itemID++ {
-		getItemMeta(&rssObj, itemID, xmlChanObj.items[itemID])
+		getItemMeta(&rssObj.channel.items[itemID], xmlChanObj.items[itemID])
+		rssObj.channel.items[itemID].resolveDate(rssObj.channel.language)
+	}
+	for liveID := range rssObj.channel.liveItems {
+		rssObj.channel.liveItems[liveID].resolveDate(rssObj.channel.language)
 	}
-	xmlDoc.Free()
 	return &rssObj, nil
 }
 
 //Searches for Channel Metadata and Populates it
-func getChannel(x *xmlRSS, root *xml.ElementNode) {
+func getChannel(x *xmlRSS, root node) {
 	for activeChannel := root.FirstChild(); activeChannel != nil; activeChannel = activeChannel.NextSibling() {
 		tag := activeChannel.Name()
 		if tag == "channel" {
@@ -97,49 +180,82 @@ func getChannel(x *xmlRSS, root *xml.ElementNode) {
 	}
 }
 
-//Get Metadata and Fetch Item nodes for Channel
-func getChannelElem(r *RSS, c xml.Node) xmlChannel {
-	x := xmlChannel{}
+//Get Metadata for Channel
+func getChannelElem(r *RSS, c node) {
 	for activeElem := c.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
-		tag := activeElem.Name()
-		namespace := activeElem.Namespace()
-		tagContent := activeElem.Content()
-		if tag == "text" {
-			continue
+		setChannelField(r, activeElem)
+	}
+}
+
+//Sets Appropriate Channel Field Given a Child of the Channel Node
+func setChannelField(r *RSS, activeElem node) {
+	tag := activeElem.Name()
+	namespace := activeElem.Namespace()
+	tagContent := activeElem.Content()
+	switch namespace {
+	case itunesNS:
+		r.channel.isItunes = true
+		setItunesMetaField(activeElem, &r.channel.itunes)
+	case mrssNS:
+		r.channel.isMRSS = true
+		setMediaChannelMetaField(activeElem, &r.channel.media)
+	case podcastNS, podcastLegacyNS:
+		r.channel.isPodcast = true
+		if tag == "liveItem" {
+			r.channel.liveItems = append(r.channel.liveItems, parseLiveItem(activeElem))
+		} else {
+			setPodcastMetaField(activeElem, &r.channel.podcast)
 		}
-		switch namespace {
-		case "http://www.itunes.com/dtds/podcast-1.0.dtd":
-			r.channel.isItunes = true
-			setItunesMetaField(activeElem, &r.channel.itunes)
-		case "http://search.yahoo.com/mrss/":
-			r.channel.isMRSS = true
-			setMediaChannelMetaField(activeElem, &r.channel.media)
-		case "":
-			switch tag {
-			case "title":
-				r.channel.title = tagContent
-			case "link":
-				r.channel.link = tagContent
-			case "generator":
-				r.channel.generator = tagContent
-			case "description":
-				r.channel.description = tagContent
-			case "language":
-				r.channel.language = tagContent
-			case "copyright":
-				r.channel.copyright = tagContent
-			case "category":
-				r.channel.categories = append(r.channel.categories, tagContent)
-			case "item":
-				x.items = append(x.items, activeElem)
+	case dcNS:
+		setDublinCoreChannelField(activeElem, &r.channel)
+	case "", rss1NS:
+		switch tag {
+		case "title":
+			r.channel.title = tagContent
+		case "link":
+			r.channel.link = tagContent
+		case "generator":
+			r.channel.generator = tagContent
+		case "description":
+			r.channel.description = tagContent
+		case "language":
+			r.channel.language = tagContent
+		case "copyright":
+			r.channel.copyright = tagContent
+		case "category":
+			r.channel.categories = append(r.channel.categories, tagContent)
+		case "image":
+			setImageFields(activeElem, &r.channel.image)
+		case "pubDate":
+			setLocalizedDate(&r.channel.pubDate, &r.channel.pubDateText, tagContent)
+		case "lastBuildDate":
+			setLocalizedDate(&r.channel.lastBuild, &r.channel.lastBuildText, tagContent)
+		case "ttl":
+			if minutes, err := strconv.Atoi(strings.TrimSpace(tagContent)); err == nil && minutes > 0 {
+				r.channel.ttl = time.Duration(minutes) * time.Minute
 			}
+		case "managingEditor":
+			r.channel.editor = tagContent
+		case "webMaster":
+			r.channel.webMaster = tagContent
+		case "docs":
+			r.channel.docs = tagContent
+		case "cloud":
+			r.channel.cloud = parseCloud(activeElem)
+		case "textInput", "textinput":
+			r.channel.textInput = parseTextInput(activeElem, r.channel.textInput)
+		case "skipHours":
+			r.channel.skipHours = parseSkipHours(activeElem)
+		case "skipDays":
+			r.channel.skipDays = parseSkipDays(activeElem)
+		case "rating":
+			r.channel.rating = tagContent
 		}
 	}
-	return x
 }
 
 //Filter out stuff that's not an item
-func getItems(x *xmlChannel, c xml.Node) {
+func getItems(x *xmlChannel, c node) {
 	for activeItem := c.FirstChild(); activeItem != nil; activeItem = activeItem.NextSibling() {
 		tag := activeItem.Name()
 		if tag == "item" {
@@ -149,83 +265,63 @@ func getItems(x *xmlChannel, c xml.Node) {
 }
 
 //Sets Appropriate Item Metadata
-func getItemMeta(r *RSS, itemID int, i xml.Node) {
-	r.channel.items[itemID].media.credits = make(map[string]string)
+func getItemMeta(item *Item, i node) {
+	if about := i.Attr("about"); about != "" { //RSS 1.0 item identifier
+		item.guid = GUIDField{IsPermaLink: false, Content: about}
+	}
 	for activeElem := i.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
 		tag := activeElem.Name()
-		if tag == "text" {
-			continue
-		}
 		tagContent := activeElem.Content()
 		namespace := activeElem.Namespace()
 		switch namespace {
-		case "http://www.itunes.com/dtds/podcast-1.0.dtd": //iTunes Podcast RSS Namespace
-			r.channel.items[itemID].isItunes = true
-			setItunesMetaField(activeElem, &r.channel.items[itemID].itunes)
-		case "http://search.yahoo.com/mrss/": //MediaRSS Namespace
-			setMediaMetaField(activeElem, &r.channel.items[itemID].media)
-		case "":
+		case itunesNS: //iTunes Podcast RSS Namespace
+			item.isItunes = true
+			setItunesMetaField(activeElem, &item.itunes)
+		case mrssNS: //MediaRSS Namespace
+			item.isMRSS = true
+			setMediaMetaField(activeElem, &item.media)
+		case contentNS:
+			if tag == "encoded" {
+				item.content = tagContent
+			}
+		case dcNS:
+			setDublinCoreItemField(activeElem, item)
+		case podcastNS, podcastLegacyNS:
+			item.isPodcast = true
+			setPodcastMetaField(activeElem, &item.podcast)
+		case "", rss1NS:
 			switch tag {
 			case "title":
-				r.channel.items[itemID].title = tagContent
+				item.title = tagContent
 			case "link":
-				r.channel.items[itemID].link = tagContent
+				item.link = tagContent
 			case "pubDate":
-				layouts := []string{
-					"2006-01-02 15:04:05 -0700 MST",
-					"2006-01-02 15:04:05 -0700",
-					"2006-01-02 15:04:05",
-					"2006/01/02 15:04:05 -0700 MST",
-					"2006/01/02 15:04:05 -0700",
-					"2006/01/02 15:04:05",
-					"2006-01-02 -0700 MST",
-					"2006-01-02 -0700",
-					"2006-01-02",
-					"2006/01/02 -0700 MST",
-					"2006/01/02 -0700",
-					"2006/01/02",
-					"2006-01-02 15:04:05 -0700 -0700",
-					"2006/01/02 15:04:05 -0700 -0700",
-					"2006-01-02 -0700 -0700",
-					"2006/01/02 -0700 -0700",
-					"Mon, 2 Jan 2006 15:04:05 MST",
-					time.ANSIC,
-					time.UnixDate,
-					time.RubyDate,
-					time.RFC822,
-					time.RFC822Z,
-					time.RFC850,
-					time.RFC1123,
-					time.RFC1123Z,
-					time.RFC3339,
-					time.RFC3339Nano,
-					time.Kitchen,
-					time.Stamp,
-					time.StampMilli,
-					time.StampMicro,
-					time.StampNano,
-				}
-				var parsedDate time.Time
-				var err error
-				for _, layout := range layouts {
-					parsedDate, err = time.Parse(layout, tagContent)
-					if err == nil {
-						r.channel.items[itemID].date = &parsedDate
-						break
-					}
-				}
+				item.setDate(tagContent)
 			case "description":
-				r.channel.items[itemID].description = tagContent
+				item.description = tagContent
+			case "author":
+				item.author = tagContent
+			case "category":
+				item.categories = append(item.categories, Category{Domain: activeElem.Attr("domain"), Value: tagContent})
+			case "guid":
+				item.guid = GUIDField{
+					IsPermaLink: !strings.EqualFold(strings.TrimSpace(activeElem.Attr("isPermaLink")), "false"),
+					Content:     strings.TrimSpace(tagContent),
+				}
+			case "comments":
+				item.comments = strings.TrimSpace(tagContent)
+			case "source":
+				item.source = &Source{URL: activeElem.Attr("url"), Title: tagContent}
 			case "enclosure":
-				r.channel.items[itemID].hasEnclosure = true
-				if urlAttr := activeElem.Attribute("url"); urlAttr != nil {
-					r.channel.items[itemID].enclosure.url = urlAttr.Value()
+				item.hasEnclosure = true
+				if urlAttr := activeElem.Attr("url"); urlAttr != "" {
+					item.enclosure.url = urlAttr
 				}
-				if mediaType := activeElem.Attribute("type"); mediaType != nil {
-					r.channel.items[itemID].enclosure.mediaType = mediaType.Value()
+				if mediaType := activeElem.Attr("type"); mediaType != "" {
+					item.enclosure.mediaType = mediaType
 				}
-				if fileSize := activeElem.Attribute("length"); fileSize != nil {
-					r.channel.items[itemID].enclosure.size, _ = strconv.ParseUint(fileSize.Value(), 10, 64)
+				if fileSize := activeElem.Attr("length"); fileSize != "" {
+					item.enclosure.size, _ = strconv.ParseUint(fileSize, 10, 64)
 				}
 			}
 		}
@@ -235,15 +331,31 @@ func getItemMeta(r *RSS, itemID int, i xml.Node) {
 //Returns available feed items. Will return an error if the feed is empty.
 func (r *RSS) Items() ([]Item, error) {
 	if len(r.channel.items) == 0 {
-		return nil, errors.New("Feed contains no items")
+		return nil, ErrNoItems
 	}
 	return r.channel.items, nil
 }
 
+//Returns the feed link. If the field is not populated, will return an empty string and an error.
+func (r *RSS) Link() (string, error) {
+	if r.channel.link == "" {
+		return "", notPopulated(ElementChannel, "", "link")
+	}
+	return r.channel.link, nil
+}
+
+//Returns the feed copyright notice. If the field is not populated, will return an empty string and an error.
+func (r *RSS) Copyright() (string, error) {
+	if r.channel.copyright == "" {
+		return "", notPopulated(ElementChannel, "", "copyright")
+	}
+	return r.channel.copyright, nil
+}
+
 //Returns the feed title. If the field is not populated, will return an empty string and an error.
 func (r *RSS) Title() (string, error) {
 	if r.channel.title == "" {
-		return "", errors.New("Feed title is not populated")
+		return "", notPopulated(ElementChannel, "", "title")
 	}
 	return r.channel.title, nil
 }
@@ -251,7 +363,7 @@ func (r *RSS) Title() (string, error) {
 //Returns the feed generator. If the field is not populated, will return an empty string and an error.
 func (r *RSS) Generator() (string, error) {
 	if r.channel.generator == "" {
-		return "", errors.New("Feed generator is not populated")
+		return "", notPopulated(ElementChannel, "", "generator")
 	}
 	return r.channel.generator, nil
 }
@@ -259,7 +371,7 @@ func (r *RSS) Generator() (string, error) {
 //Returns the feed description. If the field is not populated, will return an empty string and an error.
 func (r *RSS) Description() (string, error) {
 	if r.channel.description == "" {
-		return "", errors.New("Feed description is not populated")
+		return "", notPopulated(ElementChannel, "", "description")
 	}
 	return r.channel.description, nil
 }
@@ -267,7 +379,7 @@ func (r *RSS) Description() (string, error) {
 //Returns the feed language. This isn't always that standardized so be careful while parsing the field. An empty string and error will be returned if the field is not populated.
 func (r *RSS) Language() (string, error) {
 	if r.channel.language == "" {
-		return "", errors.New("Feed language is not populated")
+		return "", notPopulated(ElementChannel, "", "language")
 	}
 	return r.channel.language, nil
 }
@@ -275,7 +387,7 @@ func (r *RSS) Language() (string, error) {
 //Returns the feed categories. If no category tags were found for the channel, you'll get a nil result with an accompanying error.
 func (r *RSS) Categories() ([]string, error) {
 	if len(r.channel.categories) == 0 {
-		return nil, errors.New("Feed categories not populated")
+		return nil, notPopulated(ElementChannel, "", "category")
 	}
 	return r.channel.categories, nil
 }
@@ -283,23 +395,26 @@ func (r *RSS) Categories() ([]string, error) {
 //Returns the item title. If the item title is not populated, you'll get an empty string and an error.
 func (i Item) Title() (string, error) {
 	if i.title == "" {
-		return "", errors.New("Item title is not populated")
+		return "", notPopulated(ElementItem, "", "title")
 	}
 	return i.title, nil
 }
 
-//Returns the item link. If the item link is not populated, you'll get an empty string and an error.
+//Returns the item link. If the item link is not populated, a permalink GUID is returned instead. Failing that, you'll get an empty string and an error.
 func (i Item) Link() (string, error) {
-	if i.link == "" {
-		return "", errors.New("Item link is not populated")
+	if i.link != "" {
+		return i.link, nil
+	}
+	if i.guid.IsPermaLink && i.guid.Content != "" {
+		return i.guid.Content, nil
 	}
-	return i.link, nil
+	return "", notPopulated(ElementItem, "", "link")
 }
 
 //Returns the item date. If the item date is not populated, you'll get nil and an error.
 func (i Item) Date() (*time.Time, error) {
 	if i.date == nil {
-		return nil, errors.New("Item date not populated")
+		return nil, notPopulated(ElementItem, "", "pubDate")
 	}
 	return i.date, nil
 }
@@ -317,7 +432,55 @@ func (i Item) EnclosureURL() string {
 //Returns the item . If the item title is not populated, you'll get an empty string and an error.
 func (i Item) Description() (string, error) {
 	if i.description == "" {
-		return "", errors.New("Item description is not populated")
+		return "", notPopulated(ElementItem, "", "description")
 	}
 	return i.description, nil
 }
+
+//Returns the full item content, which is often HTML. If the item content is not populated, you'll get an empty string and an error.
+func (i Item) Content() (string, error) {
+	if i.content == "" {
+		return "", notPopulated(ElementItem, "", "content")
+	}
+	return i.content, nil
+}
+
+//Returns the item author. If the item author is not populated, you'll get an empty string and an error.
+func (i Item) Author() (string, error) {
+	if i.author == "" {
+		return "", notPopulated(ElementItem, "", "author")
+	}
+	return i.author, nil
+}
+
+//Returns the item categories. If no category tags were found for the item, you'll get a nil result with an accompanying error.
+func (i Item) Categories() ([]Category, error) {
+	if len(i.categories) == 0 {
+		return nil, notPopulated(ElementItem, "", "category")
+	}
+	return i.categories, nil
+}
+
+//Returns the item GUID. If the item has no GUID, you'll get an empty GUIDField and an error.
+func (i Item) GUID() (GUIDField, error) {
+	if i.guid.Content == "" {
+		return GUIDField{}, notPopulated(ElementItem, "", "guid")
+	}
+	return i.guid, nil
+}
+
+//Returns the URL of the item comments page. If the field is not populated, you'll get an empty string and an error.
+func (i Item) Comments() (string, error) {
+	if i.comments == "" {
+		return "", notPopulated(ElementItem, "", "comments")
+	}
+	return i.comments, nil
+}
+
+//Returns the channel the item was republished from. If the item has no source, you'll get nil and an error.
+func (i Item) Source() (*Source, error) {
+	if i.source == nil {
+		return nil, notPopulated(ElementItem, "", "source")
+	}
+	return i.source, nil
+}
@@ -0,0 +1,218 @@
+package easyrss
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//A single piece of timed text from a transcript
+type Cue struct {
+	Start   time.Duration
+	End     time.Duration
+	Speaker string //Empty when the format or the file doesn't identify speakers
+	Text    string
+}
+
+//Matches WebVTT voice spans, <v Speaker> or <v.class Speaker>
+var vttVoice = regexp.MustCompile(`<v(?:\.[^\s>]*)?\s+([^>]*)>`)
+
+//Matches any other markup left in cue text
+var cueTag = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+//Parses a transcript of the given MIME type. SRT, WebVTT and Podcasting 2.0 JSON transcripts are supported, other types return
+//ErrUnsupportedFormat.
+func ParseTranscript(data []byte, mediaType string) ([]Cue, error) {
+	if semicolon := strings.IndexByte(mediaType, ';'); semicolon >= 0 {
+		mediaType = mediaType[:semicolon]
+	}
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "application/srt", "application/x-subrip", "text/srt":
+		return ParseSRT(data)
+	case "text/vtt":
+		return ParseWebVTT(data)
+	case "application/json":
+		return ParseJSONTranscript(data)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, mediaType)
+}
+
+//Parses a SubRip (.srt) transcript. Speakers are picked up from WebVTT style voice spans or a leading "Name:" label.
+func ParseSRT(data []byte) ([]Cue, error) {
+	var cues []Cue
+	blocks, lines := cueBlocks(data)
+	for b, block := range blocks {
+		timing := 0
+		if !strings.Contains(block[0], "-->") { //Skip the cue number
+			timing = 1
+		}
+		if timing >= len(block) {
+			return nil, fmt.Errorf("Line %d: %w", lines[b], ErrBadTranscript)
+		}
+		cue, err := parseCueTiming(block[timing])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", lines[b]+timing, err)
+		}
+		setCueText(&cue, block[timing+1:], true)
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+//Parses a WebVTT (.vtt) transcript. NOTE, STYLE and REGION blocks are skipped and speakers are taken from voice spans.
+func ParseWebVTT(data []byte) ([]Cue, error) {
+	var cues []Cue
+	blocks, lines := cueBlocks(data)
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0][0], "WEBVTT") {
+		return nil, fmt.Errorf("Missing WEBVTT header: %w", ErrBadTranscript)
+	}
+	for b, block := range blocks[1:] {
+		switch strings.SplitN(block[0], " ", 2)[0] {
+		case "NOTE", "STYLE", "REGION":
+			continue
+		}
+		timing := 0
+		if !strings.Contains(block[0], "-->") { //Skip the cue identifier
+			timing = 1
+		}
+		if timing >= len(block) {
+			return nil, fmt.Errorf("Line %d: %w", lines[b+1], ErrBadTranscript)
+		}
+		cue, err := parseCueTiming(block[timing])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", lines[b+1]+timing, err)
+		}
+		setCueText(&cue, block[timing+1:], false)
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+//Segment of a Podcasting 2.0 JSON transcript
+type jsonTranscriptSegment struct {
+	Speaker   string  `json:"speaker"`
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+	Body      string  `json:"body"`
+}
+
+//Parses a Podcasting 2.0 JSON transcript.
+func ParseJSONTranscript(data []byte) ([]Cue, error) {
+	var transcript struct {
+		Segments []jsonTranscriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, err
+	}
+	cues := make([]Cue, 0, len(transcript.Segments))
+	for _, segment := range transcript.Segments {
+		if segment.EndTime < segment.StartTime {
+			return nil, fmt.Errorf("Segment at %gs ends before it starts: %w", segment.StartTime, ErrBadTranscript)
+		}
+		cues = append(cues, Cue{
+			Start:   secondsToDuration(segment.StartTime),
+			End:     secondsToDuration(segment.EndTime),
+			Speaker: strings.TrimSpace(segment.Speaker),
+			Text:    strings.TrimSpace(segment.Body),
+		})
+	}
+	return cues, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+//Splits a text transcript into blank-line separated blocks of trimmed lines, along with the line number each block starts on
+func cueBlocks(data []byte) ([][]string, []int) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	var blocks [][]string
+	var starts []int
+	var block []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" {
+			if block != nil {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		if block == nil {
+			starts = append(starts, lineNo)
+		}
+		block = append(block, line)
+	}
+	if block != nil {
+		blocks = append(blocks, block)
+	}
+	return blocks, starts
+}
+
+//Parses "start --> end", ignoring any WebVTT cue settings after the end time
+func parseCueTiming(line string) (Cue, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return Cue{}, ErrBadTranscript
+	}
+	end := strings.Fields(parts[1])
+	if len(end) == 0 {
+		return Cue{}, ErrBadTranscript
+	}
+	start, ok := parseCueTime(strings.TrimSpace(parts[0]))
+	if !ok {
+		return Cue{}, ErrBadTranscript
+	}
+	stop, ok := parseCueTime(end[0])
+	if !ok || stop < start {
+		return Cue{}, ErrBadTranscript
+	}
+	return Cue{Start: start, End: stop}, nil
+}
+
+//Parses HH:MM:SS,mmm (SRT), HH:MM:SS.mmm or MM:SS.mmm (WebVTT) timestamps
+func parseCueTime(stamp string) (time.Duration, bool) {
+	stamp = strings.Replace(stamp, ",", ".", 1)
+	fields := strings.Split(stamp, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err != nil || seconds < 0 || seconds >= 60 {
+		return 0, false
+	}
+	total := secondsToDuration(seconds)
+	unit := time.Minute
+	for f := len(fields) - 2; f >= 0; f-- {
+		value, err := strconv.Atoi(fields[f])
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		total += time.Duration(value) * unit
+		unit = time.Hour
+	}
+	return total, true
+}
+
+//Joins the cue text lines, pulling the speaker out of voice spans and, if allowed, a leading "Name:" label
+func setCueText(cue *Cue, lines []string, labels bool) {
+	text := strings.Join(lines, "\n")
+	if voice := vttVoice.FindStringSubmatch(text); voice != nil {
+		cue.Speaker = strings.TrimSpace(voice[1])
+	}
+	text = strings.TrimSpace(html.UnescapeString(cueTag.ReplaceAllString(text, "")))
+	if labels && cue.Speaker == "" {
+		if colon := strings.Index(text, ": "); colon > 0 && colon <= 40 && !strings.ContainsAny(text[:colon], "\n.!?") {
+			cue.Speaker, text = text[:colon], strings.TrimSpace(text[colon+2:])
+		}
+	}
+	cue.Text = text
+}
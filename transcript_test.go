@@ -0,0 +1,110 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const transcriptFeed = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<item><title>1</title><podcast:transcript url="http://p/1.vtt" type="text/vtt" rel="captions"/><podcast:transcript url="http://p/1.json" type="application/json" language="es"/>
+<podcast:chapters url="http://p/1.chapters.json" type="application/json+chapters"/></item></channel></rss>`
+
+func TestTranscriptLinks(t *testing.T) {
+	want := []Transcript{{URL: "http://p/1.vtt", Type: "text/vtt", Rel: "captions"}, {URL: "http://p/1.json", Type: "application/json", Language: "es"}}
+	r, err := Decode([]byte(transcriptFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	undeclared, err := decodeXML(pureBackend{}, []byte(strings.Replace(transcriptFeed, ` xmlns:podcast="`+podcastNS+`"`, "", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, r := range map[string]*RSS{"decode": r, "stream": streamFeed(t, transcriptFeed), "round trip": decoded, "undeclared prefix": undeclared} {
+		items, _ := r.Items()
+		if got, err := items[0].Transcripts(); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s transcripts = %+v, %v", name, got, err)
+		}
+		if got, err := items[0].Chapters(); err != nil || *got != (ChaptersLink{URL: "http://p/1.chapters.json", Type: "application/json+chapters"}) {
+			t.Errorf("%s chapters = %+v, %v", name, got, err)
+		}
+	}
+}
+
+func TestParseTranscript(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		data      string
+		want      []Cue
+	}{
+		{"srt", "application/x-subrip",
+			"\ufeff1\r\n00:00:01,600 --> 00:00:04,200\r\nAlice: Hello &amp; welcome\r\n\r\n2\r\n00:00:05,000 --> 00:01:02,000\r\n<i>second</i>\r\nline\r\n",
+			[]Cue{{Start: 1600 * time.Millisecond, End: 4200 * time.Millisecond, Speaker: "Alice", Text: "Hello & welcome"},
+				{Start: 5 * time.Second, End: 62 * time.Second, Text: "second\nline"}}},
+		{"srt without numbers", "text/srt", "00:00:01,000 --> 00:00:02,000\nx\n",
+			[]Cue{{Start: time.Second, End: 2 * time.Second, Text: "x"}}},
+		{"webvtt", "text/vtt; charset=utf-8",
+			"WEBVTT - test\n\nNOTE a note\nmore\n\nintro\n00:01.000 --> 00:02.500 align:start\n<v.loud Bob>Hi there</v>\n\n01:00:00.000 --> 01:00:01.000\nbye\n",
+			[]Cue{{Start: time.Second, End: 2500 * time.Millisecond, Speaker: "Bob", Text: "Hi there"},
+				{Start: time.Hour, End: time.Hour + time.Second, Text: "bye"}}},
+		{"json", "application/json", `{"version":"1.0.0","segments":[{"speaker":"A","startTime":0.5,"endTime":1,"body":"x"}]}`,
+			[]Cue{{Start: 500 * time.Millisecond, End: time.Second, Speaker: "A", Text: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTranscript([]byte(tt.data), tt.mediaType)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTranscript = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTranscriptErrors(t *testing.T) {
+	if _, err := ParseTranscript(nil, "text/html"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("html error = %v, want ErrUnsupportedFormat", err)
+	}
+	bad := []string{
+		"1\n00:00:05,000 --> 00:00:01,000\nx", //Ends before it starts
+		"1\n00:00:xx,000 --> 00:00:01,000\nx",
+		"1\n",
+	}
+	for _, data := range bad {
+		if _, err := ParseSRT([]byte(data)); !errors.Is(err, ErrBadTranscript) {
+			t.Errorf("ParseSRT(%q) error = %v, want ErrBadTranscript", data, err)
+		}
+	}
+	if _, err := ParseWebVTT([]byte("00:01.000 --> 00:02.000\nx")); !errors.Is(err, ErrBadTranscript) {
+		t.Errorf("WebVTT without header error = %v, want ErrBadTranscript", err)
+	}
+}
+
+func TestParseChapters(t *testing.T) {
+	got, err := ParseChapters([]byte(`{"version":"1.2.0","chapters":[{"startTime":60,"title":"B","toc":false},{"startTime":0,"title":"A","img":"i","url":"u"},
+{"startTime":90,"endTime":120,"title":"C"}]}`))
+	want := []Chapter{
+		{Start: 0, End: time.Minute, Title: "A", Image: "i", URL: "u", TOC: true},
+		{Start: time.Minute, End: 90 * time.Second, Title: "B"},
+		{Start: 90 * time.Second, End: 2 * time.Minute, Title: "C", TOC: true},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseChapters = %+v, %v, want %+v", got, err, want)
+	}
+	if _, err := ParseChapters([]byte(`{"version":"1.2.0"}`)); !errors.Is(err, ErrBadChapters) {
+		t.Errorf("missing chapters error = %v, want ErrBadChapters", err)
+	}
+	if _, err := ParseChapters([]byte(`not json`)); err == nil {
+		t.Error("invalid JSON accepted")
+	}
+}
@@ -0,0 +1,233 @@
+package easyrss
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//A Podcasting 2.0 <podcast:value> block describing how listeners can stream payments to the people behind a podcast or episode.
+//Item-level blocks replace the channel-level ones, see RSS.ValuesFor.
+type Value struct {
+	Type       string //Payment layer, e.g. "lightning"
+	Method     string //Transport, e.g. "keysend"
+	Suggested  string //Suggested amount per minute, in the payment layer's units
+	Recipients []ValueRecipient
+	TimeSplits []ValueTimeSplit //Ranges of the episode that pay someone else, e.g. a song from another podcast
+}
+
+//A <podcast:valueRecipient>. Splits of non-fee recipients are shares, not percentages: a payment is divided in proportion to them.
+//Fee recipients take their split as a percentage of the whole payment first.
+type ValueRecipient struct {
+	Name        string
+	Type        string //Address type, e.g. "node"
+	Address     string
+	CustomKey   string
+	CustomValue string
+	Split       int
+	Fee         bool
+}
+
+//A <podcast:valueTimeSplit>. While playback is inside the range, RemotePercentage of the payment goes to the recipients below and
+//the rest to the enclosing value block.
+type ValueTimeSplit struct {
+	Start            time.Duration
+	Duration         time.Duration
+	RemoteStart      time.Duration //Where the remote item starts playing from
+	RemotePercentage int           //Defaults to 100
+	Recipients       []ValueRecipient
+	RemoteItem       *RemoteItem //Set when the recipients should be taken from another feed's item
+}
+
+//A <podcast:remoteItem> pointing at an item in another feed
+type RemoteItem struct {
+	FeedGUID string
+	FeedURL  string
+	ItemGUID string
+	Medium   string
+}
+
+func parseValue(n node) Value {
+	value := Value{Type: n.Attr("type"), Method: n.Attr("method"), Suggested: n.Attr("suggested")}
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		switch activeElem.Name() {
+		case "valueRecipient":
+			value.Recipients = append(value.Recipients, parseValueRecipient(activeElem))
+		case "valueTimeSplit":
+			value.TimeSplits = append(value.TimeSplits, parseValueTimeSplit(activeElem))
+		}
+	}
+	return value
+}
+
+func parseValueRecipient(n node) ValueRecipient {
+	split, _ := strconv.Atoi(strings.TrimSpace(n.Attr("split")))
+	return ValueRecipient{
+		Name:        n.Attr("name"),
+		Type:        n.Attr("type"),
+		Address:     n.Attr("address"),
+		CustomKey:   n.Attr("customKey"),
+		CustomValue: n.Attr("customValue"),
+		Split:       split,
+		Fee:         strings.EqualFold(strings.TrimSpace(n.Attr("fee")), "true"),
+	}
+}
+
+func parseValueTimeSplit(n node) ValueTimeSplit {
+	split := ValueTimeSplit{
+		Start:            parseSeconds(n.Attr("startTime")),
+		Duration:         parseSeconds(n.Attr("duration")),
+		RemoteStart:      parseSeconds(n.Attr("remoteStartTime")),
+		RemotePercentage: 100,
+	}
+	if percentage, err := strconv.Atoi(strings.TrimSpace(n.Attr("remotePercentage"))); err == nil {
+		split.RemotePercentage = percentage
+	}
+	for activeElem := n.FirstChild(); activeElem != nil; activeElem = activeElem.NextSibling() {
+		switch activeElem.Name() {
+		case "valueRecipient":
+			split.Recipients = append(split.Recipients, parseValueRecipient(activeElem))
+		case "remoteItem":
+			split.RemoteItem = &RemoteItem{
+				FeedGUID: activeElem.Attr("feedGuid"),
+				FeedURL:  activeElem.Attr("feedUrl"),
+				ItemGUID: activeElem.Attr("itemGuid"),
+				Medium:   activeElem.Attr("medium"),
+			}
+		}
+	}
+	return split
+}
+
+//Parses a decimal number of seconds, returning zero if it can't
+func parseSeconds(attr string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(attr), 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return secondsToDuration(seconds)
+}
+
+//Checks the value block can actually be paid out: every recipient needs an address and a non-negative split, fees can't take more
+//than the whole payment, and unless fees take all of it there must be a recipient with a positive share. Time splits are checked
+//the same way, except those delegating to a remote item. Problems are reported wrapping ErrInvalidValue.
+func (v Value) Validate() error {
+	if err := validateRecipients(v.Recipients); err != nil {
+		return err
+	}
+	for _, split := range v.TimeSplits {
+		if split.RemotePercentage < 0 || split.RemotePercentage > 100 {
+			return fmt.Errorf("Time split at %v has remote percentage %d: %w", split.Start, split.RemotePercentage, ErrInvalidValue)
+		}
+		if split.Duration <= 0 {
+			return fmt.Errorf("Time split at %v has no duration: %w", split.Start, ErrInvalidValue)
+		}
+		if split.RemoteItem != nil && len(split.Recipients) == 0 {
+			continue
+		}
+		if err := validateRecipients(split.Recipients); err != nil {
+			return fmt.Errorf("Time split at %v: %w", split.Start, err)
+		}
+	}
+	return nil
+}
+
+func validateRecipients(recipients []ValueRecipient) error {
+	if len(recipients) == 0 {
+		return fmt.Errorf("No recipients: %w", ErrInvalidValue)
+	}
+	fees, shares := 0, 0
+	for _, recipient := range recipients {
+		if recipient.Address == "" {
+			return fmt.Errorf("Recipient %q has no address: %w", recipient.Name, ErrInvalidValue)
+		}
+		if recipient.Split < 0 {
+			return fmt.Errorf("Recipient %q has a negative split: %w", recipient.Name, ErrInvalidValue)
+		}
+		if recipient.Fee {
+			fees += recipient.Split
+		} else {
+			shares += recipient.Split
+		}
+	}
+	if fees > 100 {
+		return fmt.Errorf("Fees add up to %d%%: %w", fees, ErrInvalidValue)
+	}
+	if shares == 0 && fees < 100 {
+		return fmt.Errorf("Splits add up to zero: %w", ErrInvalidValue)
+	}
+	return nil
+}
+
+//Divides a payment of amount units among the recipients, returning one amount per recipient in order. Fees are taken off the top,
+//the remainder is split in proportion to the shares. Amounts are rounded down, so a few units may be left over.
+func (v Value) Amounts(amount uint64) ([]uint64, error) {
+	if err := validateRecipients(v.Recipients); err != nil {
+		return nil, err
+	}
+	return splitAmount(v.Recipients, amount), nil
+}
+
+func splitAmount(recipients []ValueRecipient, amount uint64) []uint64 {
+	amounts := make([]uint64, len(recipients))
+	remainder, shares := amount, uint64(0)
+	for r, recipient := range recipients {
+		if recipient.Fee {
+			amounts[r] = amount * uint64(recipient.Split) / 100
+			remainder -= amounts[r]
+		} else {
+			shares += uint64(recipient.Split)
+		}
+	}
+	if shares == 0 {
+		return amounts
+	}
+	for r, recipient := range recipients {
+		if !recipient.Fee {
+			amounts[r] = remainder * uint64(recipient.Split) / shares
+		}
+	}
+	return amounts
+}
+
+//Returns the time split covering offset into the episode, or nil if the enclosing value block's recipients apply.
+func (v Value) TimeSplitAt(offset time.Duration) *ValueTimeSplit {
+	for s := range v.TimeSplits {
+		if split := &v.TimeSplits[s]; offset >= split.Start && offset < split.Start+split.Duration {
+			return split
+		}
+	}
+	return nil
+}
+
+//Returns the channel's value blocks. If the channel doesn't contain Podcasting 2.0 Extensions or has no "value" tags, will return nil and an error.
+func (r *RSS) PodcastValues() ([]Value, error) {
+	if !r.channel.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(r.channel.podcast.values) == 0 {
+		return nil, notPopulated(ElementChannel, NamespacePodcast, "value")
+	}
+	return r.channel.podcast.values, nil
+}
+
+//Returns the value blocks that apply to an item of this feed: the item's own if it has any, the channel's otherwise. If neither
+//has a value block, will return nil and an error.
+func (r *RSS) ValuesFor(i *Item) ([]Value, error) {
+	if values, err := i.PodcastValues(); err == nil {
+		return values, nil
+	}
+	return r.PodcastValues()
+}
+
+//Returns the item's own value blocks. If the item doesn't contain Podcasting 2.0 Extensions or has no "value" tags, will return nil and an error.
+func (i *Item) PodcastValues() ([]Value, error) {
+	if !i.isPodcast {
+		return nil, ErrNotPodcast
+	}
+	if len(i.podcast.values) == 0 {
+		return nil, notPopulated(ElementItem, NamespacePodcast, "value")
+	}
+	return i.podcast.values, nil
+}
@@ -0,0 +1,191 @@
+package easyrss
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const valueFeed = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<podcast:value type="lightning" method="keysend" suggested="0.00000005000">
+<podcast:valueRecipient name="Host" type="node" address="02abc" split="90"/>
+<podcast:valueRecipient name="App" type="node" address="03def" customKey="696969" customValue="x" split="10"/>
+<podcast:valueRecipient name="Fee" type="node" address="04fee" split="1" fee="true"/>
+</podcast:value>
+<podcast:liveItem status="pending" start="2021-09-26T07:30:00.000-0600" end="2021-09-26T09:30:00.000-0600">
+<title>Live</title><guid>live-1</guid><enclosure url="http://s/stream.mp3" type="audio/mpeg" length="312"/><podcast:contentLink href="https://yt/x">YouTube</podcast:contentLink>
+</podcast:liveItem>
+<item><title>1</title><podcast:value type="lightning" method="keysend">
+<podcast:valueRecipient name="Guest" address="05" split="50"/><podcast:valueRecipient name="Host" address="02abc" split="50"/>
+<podcast:valueTimeSplit startTime="0" duration="30"><podcast:valueRecipient name="Intro" address="06" split="1"/></podcast:valueTimeSplit>
+<podcast:valueTimeSplit startTime="60" duration="237" remotePercentage="95"><podcast:remoteItem itemGuid="ig" feedGuid="fg"/></podcast:valueTimeSplit>
+</podcast:value></item>
+<item><title>2</title></item></channel></rss>`
+
+func checkValues(t *testing.T, r *RSS) {
+	t.Helper()
+	values, err := r.PodcastValues()
+	if err != nil || len(values) != 1 {
+		t.Fatalf("values = %+v, %v", values, err)
+	}
+	want := []ValueRecipient{
+		{Name: "Host", Type: "node", Address: "02abc", Split: 90},
+		{Name: "App", Type: "node", Address: "03def", CustomKey: "696969", CustomValue: "x", Split: 10},
+		{Name: "Fee", Type: "node", Address: "04fee", Split: 1, Fee: true},
+	}
+	if v := values[0]; v.Type != "lightning" || v.Method != "keysend" || v.Suggested != "0.00000005000" || !reflect.DeepEqual(v.Recipients, want) {
+		t.Errorf("channel value = %+v", v)
+	}
+	items, _ := r.Items()
+	itemValues, err := r.ValuesFor(&items[0])
+	if err != nil || len(itemValues) != 1 || itemValues[0].Recipients[0].Name != "Guest" {
+		t.Fatalf("item values = %+v, %v", itemValues, err)
+	}
+	splits := itemValues[0].TimeSplits
+	wantSplits := []ValueTimeSplit{
+		{Duration: 30 * time.Second, RemotePercentage: 100, Recipients: []ValueRecipient{{Name: "Intro", Address: "06", Split: 1}}},
+		{Start: time.Minute, Duration: 237 * time.Second, RemotePercentage: 95, RemoteItem: &RemoteItem{FeedGUID: "fg", ItemGUID: "ig"}},
+	}
+	if !reflect.DeepEqual(splits, wantSplits) {
+		t.Errorf("time splits = %+v, want %+v", splits, wantSplits)
+	}
+	if fallback, err := r.ValuesFor(&items[1]); err != nil || len(fallback) != 1 || fallback[0].Recipients[0].Name != "Host" {
+		t.Errorf("item without a value block = %+v, %v", fallback, err)
+	}
+
+	live, err := r.LiveItems()
+	if err != nil || len(live) != 1 {
+		t.Fatalf("live items = %+v, %v", live, err)
+	}
+	if title, _ := live[0].Title(); title != "Live" || live[0].EnclosureURL() != "http://s/stream.mp3" {
+		t.Errorf("live item title = %q, enclosure = %q", title, live[0].EnclosureURL())
+	}
+	if links, _ := live[0].PodcastContentLinks(); len(links) != 1 || links[0].Text != "YouTube" || links[0].Href != "https://yt/x" {
+		t.Errorf("content links = %+v", links)
+	}
+}
+
+func TestValue(t *testing.T) {
+	checkAllPaths(t, valueFeed, checkValues)
+}
+
+//startTime is required even at 0
+func TestValueEncodesStartTime(t *testing.T) {
+	r, err := Decode([]byte(valueFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Encode(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `<podcast:valueTimeSplit startTime="0" duration="30">`) {
+		t.Errorf("time split starting at 0 encoded without startTime:\n%s", out)
+	}
+}
+
+//Numbers that can't be read fall back to their defaults, leaving Validate to reject the block
+func TestValueMalformed(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0"><channel><title>P</title><link>http://p</link><description>D</description>
+<podcast:value type="lightning" method="keysend"><podcast:valueRecipient name="Host" address="02abc" split="ten" fee="yes"/>
+<podcast:valueTimeSplit startTime="-5" duration="long" remotePercentage="most"><podcast:valueRecipient name="Guest" address="05" split="1"/></podcast:valueTimeSplit>
+</podcast:value><item><title>1</title></item></channel></rss>`
+	checkAllPaths(t, doc, func(t *testing.T, r *RSS) {
+		values, err := r.PodcastValues()
+		if err != nil || len(values) != 1 {
+			t.Fatalf("values = %+v, %v", values, err)
+		}
+		if want := []ValueRecipient{{Name: "Host", Address: "02abc"}}; !reflect.DeepEqual(values[0].Recipients, want) {
+			t.Errorf("recipients = %+v, want %+v", values[0].Recipients, want)
+		}
+		want := []ValueTimeSplit{{RemotePercentage: 100, Recipients: []ValueRecipient{{Name: "Guest", Address: "05", Split: 1}}}}
+		if !reflect.DeepEqual(values[0].TimeSplits, want) {
+			t.Errorf("time splits = %+v, want %+v", values[0].TimeSplits, want)
+		}
+		if err := values[0].Validate(); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("Validate error = %v, want ErrInvalidValue", err)
+		}
+	})
+}
+
+func TestValueValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+		ok    bool
+	}{
+		{"shares", Value{Recipients: []ValueRecipient{{Address: "a", Split: 90}, {Address: "b", Split: 10}}}, true},
+		{"fees take everything", Value{Recipients: []ValueRecipient{{Address: "a", Split: 100, Fee: true}}}, true},
+		{"no recipients", Value{}, false},
+		{"missing address", Value{Recipients: []ValueRecipient{{Name: "a", Split: 1}}}, false},
+		{"negative split", Value{Recipients: []ValueRecipient{{Address: "a", Split: 2}, {Address: "b", Split: -1}}}, false},
+		{"fees over 100", Value{Recipients: []ValueRecipient{{Address: "a", Split: 60, Fee: true}, {Address: "b", Split: 50, Fee: true}}}, false},
+		{"zero shares", Value{Recipients: []ValueRecipient{{Address: "a", Split: 0}, {Address: "b", Split: 5, Fee: true}}}, false},
+		{"remote split", Value{Recipients: []ValueRecipient{{Address: "a", Split: 1}},
+			TimeSplits: []ValueTimeSplit{{Duration: time.Second, RemotePercentage: 100, RemoteItem: &RemoteItem{ItemGUID: "x"}}}}, true},
+		{"split without duration", Value{Recipients: []ValueRecipient{{Address: "a", Split: 1}},
+			TimeSplits: []ValueTimeSplit{{RemotePercentage: 100, RemoteItem: &RemoteItem{ItemGUID: "x"}}}}, false},
+		{"split percentage", Value{Recipients: []ValueRecipient{{Address: "a", Split: 1}},
+			TimeSplits: []ValueTimeSplit{{Duration: time.Second, RemotePercentage: 101, RemoteItem: &RemoteItem{ItemGUID: "x"}}}}, false},
+		{"split without recipients", Value{Recipients: []ValueRecipient{{Address: "a", Split: 1}},
+			TimeSplits: []ValueTimeSplit{{Duration: time.Second, RemotePercentage: 100}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.Validate()
+			if tt.ok && err != nil || !tt.ok && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestValueAmounts(t *testing.T) {
+	v := Value{Recipients: []ValueRecipient{{Address: "a", Split: 90}, {Address: "b", Split: 10}, {Address: "c", Split: 1, Fee: true}}}
+	if got, err := v.Amounts(1000); err != nil || !reflect.DeepEqual(got, []uint64{891, 99, 10}) {
+		t.Errorf("Amounts(1000) = %v, %v", got, err)
+	}
+	if _, err := (Value{}).Amounts(1000); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Amounts without recipients error = %v", err)
+	}
+	splits := Value{TimeSplits: []ValueTimeSplit{{Duration: 30 * time.Second}, {Start: time.Minute, Duration: time.Minute}}}
+	tests := []struct {
+		offset time.Duration
+		want   int //Index of the expected split, -1 for none
+	}{
+		{0, 0}, {29 * time.Second, 0}, {30 * time.Second, -1}, {time.Minute, 1}, {2 * time.Minute, -1},
+	}
+	for _, tt := range tests {
+		got := splits.TimeSplitAt(tt.offset)
+		if tt.want < 0 && got != nil || tt.want >= 0 && got != &splits.TimeSplits[tt.want] {
+			t.Errorf("TimeSplitAt(%v) = %+v", tt.offset, got)
+		}
+	}
+}
+
+func TestLiveItemState(t *testing.T) {
+	r, err := Decode([]byte(valueFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, _ := r.LiveItems()
+	start, err := live[0].Start()
+	if err != nil || !start.Equal(time.Date(2021, 9, 26, 13, 30, 0, 0, time.UTC)) {
+		t.Fatalf("start = %v, %v", start, err)
+	}
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{start.Add(-time.Minute), LivePending},
+		{start.Add(time.Minute), LiveLive},
+		{start.Add(3 * time.Hour), LiveEnded},
+	}
+	for _, tt := range tests {
+		if got := live[0].State(tt.at); got != tt.want {
+			t.Errorf("State(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,519 @@
+package easyrss
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	startToken tokenKind = iota
+	endToken
+	textToken
+)
+
+type xmlAttr struct {
+	prefix string
+	name   string
+	value  string
+}
+
+type xmlToken struct {
+	kind        tokenKind
+	prefix      string    //Element prefix for start and end tokens
+	name        string    //Element local name for start and end tokens
+	attrs       []xmlAttr //Start token attributes
+	selfClosing bool      //Start token was written as <name/>
+	text        string    //Decoded character data for text tokens
+}
+
+//An error-tolerant XML tokenizer. Unlike encoding/xml it never rejects a document: unknown entities and stray ampersands are passed
+//through, unquoted attributes are accepted, a '<' that doesn't open a tag is treated as text and invalid UTF-8 is reinterpreted as
+//Windows-1252. Documents declaring a character set it doesn't know are read as UTF-8 the same way. Only I/O errors and UTF-32
+//documents are reported.
+type xmlTokenizer struct {
+	r   *bufio.Reader
+	err error //Reported by the first call to next
+}
+
+//Matches the encoding pseudo-attribute of an XML declaration
+var xmlDeclEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*encoding\s*=\s*["']([A-Za-z0-9._-]+)["']`)
+
+func newXMLTokenizer(r io.Reader) *xmlTokenizer {
+	br := bufio.NewReaderSize(r, 64*1024)
+	head, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte{0xef, 0xbb, 0xbf}):
+		br.Discard(3)
+	case bytes.Equal(head, []byte{0, 0, 0xfe, 0xff}), bytes.Equal(head, []byte{0xff, 0xfe, 0, 0}),
+		bytes.Equal(head, []byte{0, 0, 0, '<'}), bytes.Equal(head, []byte{'<', 0, 0, 0}):
+		return &xmlTokenizer{r: br, err: fmt.Errorf("%w: UTF-32", ErrUnsupportedEncoding)}
+	case bytes.HasPrefix(head, []byte{0xfe, 0xff}):
+		br.Discard(2)
+		return &xmlTokenizer{r: newDecodingReader(utf16Decoder(br, true))}
+	case bytes.HasPrefix(head, []byte{0xff, 0xfe}):
+		br.Discard(2)
+		return &xmlTokenizer{r: newDecodingReader(utf16Decoder(br, false))}
+	case bytes.Equal(head, []byte{0, '<', 0, '?'}):
+		return &xmlTokenizer{r: newDecodingReader(utf16Decoder(br, true))}
+	case bytes.Equal(head, []byte{'<', 0, '?', 0}):
+		return &xmlTokenizer{r: newDecodingReader(utf16Decoder(br, false))}
+	}
+	head, _ = br.Peek(512)
+	if m := xmlDeclEncoding.FindSubmatch(head); m != nil {
+		switch encoding := strings.ToLower(string(m[1])); encoding {
+		case "utf-8", "utf8", "us-ascii", "ascii":
+		case "utf-16", "utf-16le", "utf-16be", "ucs-2":
+			//Declared as UTF-16 but the bytes say otherwise, most likely transcoded on the way without updating the declaration
+		case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+			br = newDecodingReader(byteDecoder(br, decodeWindows1252))
+		case "iso-8859-15", "iso8859-15", "latin9", "latin-9":
+			br = newDecodingReader(byteDecoder(br, decodeLatin9))
+		default:
+			//Anything else is read as UTF-8, which keeps at least the ASCII markup and text of most legacy encodings intact
+		}
+	}
+	return &xmlTokenizer{r: br}
+}
+
+//Returns the next token, or io.EOF once the input is exhausted.
+func (t *xmlTokenizer) next() (xmlToken, error) {
+	if t.err != nil {
+		return xmlToken{}, t.err
+	}
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			return xmlToken{}, err
+		}
+		if c != '<' {
+			t.r.UnreadByte()
+			text, err := t.readUntil('<', false)
+			if err != nil && err != io.EOF {
+				return xmlToken{}, err
+			}
+			return xmlToken{kind: textToken, text: decodeEntities(text)}, nil
+		}
+		peek, err := t.r.Peek(1)
+		if err != nil {
+			return xmlToken{kind: textToken, text: "<"}, nil
+		}
+		switch {
+		case peek[0] == '!':
+			if t.hasPrefix("![CDATA[") {
+				t.r.Discard(8)
+				text, err := t.readPast("]]>")
+				if err != nil && err != io.EOF {
+					return xmlToken{}, err
+				}
+				return xmlToken{kind: textToken, text: fixEncoding(text)}, nil
+			}
+			if t.hasPrefix("!--") {
+				t.r.Discard(3)
+				_, err = t.readPast("-->")
+			} else {
+				err = t.skipDeclaration()
+			}
+		case peek[0] == '?':
+			_, err = t.readPast("?>")
+		case peek[0] == '/':
+			t.r.Discard(1)
+			var name string
+			name, err = t.readUntil('>', true)
+			prefix, local := splitName(strings.TrimSpace(name))
+			if local != "" {
+				return xmlToken{kind: endToken, prefix: prefix, name: local}, nil
+			}
+		case isNameStart(peek[0]):
+			return t.readStartTag()
+		default:
+			return xmlToken{kind: textToken, text: "<"}, nil
+		}
+		if err != nil && err != io.EOF {
+			return xmlToken{}, err
+		}
+	}
+}
+
+//Reads a start tag after its opening '<'
+func (t *xmlTokenizer) readStartTag() (xmlToken, error) {
+	tok := xmlToken{kind: startToken}
+	tok.prefix, tok.name = splitName(t.readName())
+	for {
+		t.skipSpace()
+		c, err := t.r.ReadByte()
+		if err != nil {
+			return tok, nil //Unterminated tag at EOF, keep what we have
+		}
+		switch c {
+		case '>':
+			return tok, nil
+		case '/':
+			if peek, _ := t.r.Peek(1); len(peek) == 1 && peek[0] == '>' {
+				t.r.Discard(1)
+				tok.selfClosing = true
+				return tok, nil
+			}
+			continue
+		case '"', '\'', '=':
+			continue //Garbage between attributes
+		}
+		t.r.UnreadByte()
+		attr := xmlAttr{}
+		attr.prefix, attr.name = splitName(t.readName())
+		t.skipSpace()
+		if peek, _ := t.r.Peek(1); len(peek) == 1 && peek[0] == '=' {
+			t.r.Discard(1)
+			t.skipSpace()
+			attr.value = t.readAttrValue()
+		}
+		if attr.name != "" {
+			tok.attrs = append(tok.attrs, attr)
+		}
+	}
+}
+
+//Reads a quoted or unquoted attribute value
+func (t *xmlTokenizer) readAttrValue() string {
+	peek, err := t.r.Peek(1)
+	if err != nil {
+		return ""
+	}
+	if quote := peek[0]; quote == '"' || quote == '\'' {
+		t.r.Discard(1)
+		value, _ := t.readUntil(quote, true)
+		return decodeEntities(value)
+	}
+	var value []byte
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if c == '>' || isSpace(c) {
+			t.r.UnreadByte()
+			break
+		}
+		value = append(value, c)
+	}
+	return decodeEntities(string(value))
+}
+
+//Reads an element or attribute name
+func (t *xmlTokenizer) readName() string {
+	var name []byte
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if isSpace(c) || c == '>' || c == '/' || c == '=' {
+			t.r.UnreadByte()
+			break
+		}
+		name = append(name, c)
+	}
+	return string(name)
+}
+
+func (t *xmlTokenizer) skipSpace() {
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			return
+		}
+		if !isSpace(c) {
+			t.r.UnreadByte()
+			return
+		}
+	}
+}
+
+func (t *xmlTokenizer) hasPrefix(prefix string) bool {
+	peek, _ := t.r.Peek(len(prefix))
+	return string(peek) == prefix
+}
+
+//Reads up to delim. The delimiter is consumed if consume is set, otherwise it is left for the next read.
+func (t *xmlTokenizer) readUntil(delim byte, consume bool) (string, error) {
+	var buf []byte
+	for {
+		chunk, err := t.r.ReadSlice(delim)
+		buf = append(buf, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return string(buf), err
+		}
+		buf = buf[:len(buf)-1]
+		if !consume {
+			t.r.UnreadByte()
+		}
+		return string(buf), nil
+	}
+}
+
+//Reads up to and including terminator, returning everything before it
+func (t *xmlTokenizer) readPast(terminator string) (string, error) {
+	last := terminator[len(terminator)-1]
+	var buf []byte
+	for {
+		chunk, err := t.r.ReadSlice(last)
+		buf = append(buf, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return string(buf), err
+		}
+		if bytes.HasSuffix(buf, []byte(terminator)) {
+			return string(buf[:len(buf)-len(terminator)]), nil
+		}
+	}
+}
+
+//Skips a <!DOCTYPE ...> style declaration, including any internal subset
+func (t *xmlTokenizer) skipDeclaration() error {
+	depth := 0
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '>':
+			if depth <= 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':' || c >= 0x80
+}
+
+//Splits a qualified name into prefix and local name
+func splitName(name string) (string, string) {
+	if colon := strings.IndexByte(name, ':'); colon > 0 {
+		return name[:colon], name[colon+1:]
+	}
+	return "", name
+}
+
+//Named entities understood beyond the five predefined by XML. Feeds frequently embed HTML entities without declaring them.
+var namedEntities = map[string]rune{
+	"amp": '&', "lt": '<', "gt": '>', "quot": '"', "apos": '\'',
+	"nbsp": ' ', "iexcl": '¡', "cent": '¢', "pound": '£', "curren": '¤', "yen": '¥', "brvbar": '¦', "sect": '§',
+	"uml": '¨', "copy": '©', "ordf": 'ª', "laquo": '«', "not": '¬', "shy": '­', "reg": '®', "macr": '¯',
+	"deg": '°', "plusmn": '±', "sup2": '²', "sup3": '³', "acute": '´', "micro": 'µ', "para": '¶', "middot": '·',
+	"cedil": '¸', "sup1": '¹', "ordm": 'º', "raquo": '»', "frac14": '¼', "frac12": '½', "frac34": '¾', "iquest": '¿',
+	"Agrave": 'À', "Aacute": 'Á', "Acirc": 'Â', "Atilde": 'Ã', "Auml": 'Ä', "Aring": 'Å', "AElig": 'Æ', "Ccedil": 'Ç',
+	"Egrave": 'È', "Eacute": 'É', "Ecirc": 'Ê', "Euml": 'Ë', "Igrave": 'Ì', "Iacute": 'Í', "Icirc": 'Î', "Iuml": 'Ï',
+	"ETH": 'Ð', "Ntilde": 'Ñ', "Ograve": 'Ò', "Oacute": 'Ó', "Ocirc": 'Ô', "Otilde": 'Õ', "Ouml": 'Ö', "times": '×',
+	"Oslash": 'Ø', "Ugrave": 'Ù', "Uacute": 'Ú', "Ucirc": 'Û', "Uuml": 'Ü', "Yacute": 'Ý', "THORN": 'Þ', "szlig": 'ß',
+	"agrave": 'à', "aacute": 'á', "acirc": 'â', "atilde": 'ã', "auml": 'ä', "aring": 'å', "aelig": 'æ', "ccedil": 'ç',
+	"egrave": 'è', "eacute": 'é', "ecirc": 'ê', "euml": 'ë', "igrave": 'ì', "iacute": 'í', "icirc": 'î', "iuml": 'ï',
+	"eth": 'ð', "ntilde": 'ñ', "ograve": 'ò', "oacute": 'ó', "ocirc": 'ô', "otilde": 'õ', "ouml": 'ö', "divide": '÷',
+	"oslash": 'ø', "ugrave": 'ù', "uacute": 'ú', "ucirc": 'û', "uuml": 'ü', "yacute": 'ý', "thorn": 'þ', "yuml": 'ÿ',
+	"OElig": 'Œ', "oelig": 'œ', "Scaron": 'Š', "scaron": 'š', "Yuml": 'Ÿ', "fnof": 'ƒ', "circ": 'ˆ', "tilde": '˜',
+	"ensp": ' ', "emsp": ' ', "thinsp": ' ', "zwnj": '‌', "zwj": '‍', "lrm": '‎', "rlm": '‏',
+	"ndash": '–', "mdash": '—', "lsquo": '‘', "rsquo": '’', "sbquo": '‚', "ldquo": '“', "rdquo": '”', "bdquo": '„',
+	"dagger": '†', "Dagger": '‡', "bull": '•', "hellip": '…', "permil": '‰', "prime": '′', "Prime": '″', "lsaquo": '‹',
+	"rsaquo": '›', "euro": '€', "trade": '™', "larr": '←', "rarr": '→', "uarr": '↑', "darr": '↓', "harr": '↔',
+}
+
+//Replaces character and entity references. Anything that isn't a recognizable reference is left as is.
+func decodeEntities(s string) string {
+	s = fixEncoding(s)
+	amp := strings.IndexByte(s, '&')
+	if amp < 0 {
+		return s
+	}
+	var b strings.Builder
+	for amp >= 0 {
+		b.WriteString(s[:amp])
+		s = s[amp:]
+		semi := strings.IndexByte(s, ';')
+		replaced := false
+		if semi > 1 && semi <= 12 {
+			ref := s[1:semi]
+			if ref[0] == '#' {
+				var code uint64
+				var err error
+				if len(ref) > 1 && (ref[1] == 'x' || ref[1] == 'X') {
+					code, err = strconv.ParseUint(ref[2:], 16, 32)
+				} else {
+					code, err = strconv.ParseUint(ref[1:], 10, 32)
+				}
+				if err == nil && utf8.ValidRune(rune(code)) {
+					b.WriteRune(rune(code))
+					replaced = true
+				}
+			} else if r, ok := namedEntities[ref]; ok {
+				b.WriteRune(r)
+				replaced = true
+			}
+		}
+		if replaced {
+			s = s[semi+1:]
+		} else {
+			b.WriteByte('&')
+			s = s[1:]
+		}
+		amp = strings.IndexByte(s, '&')
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+//Windows-1252 code points for bytes 0x80-0x9F. Latin-1 maps these to C1 controls, which never appear in real text.
+var cp1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+func decodeWindows1252(b byte) rune {
+	if b >= 0x80 && b < 0xa0 {
+		return cp1252[b-0x80]
+	}
+	return rune(b)
+}
+
+//Reinterprets bytes that aren't valid UTF-8 as Windows-1252, the most common mislabelled feed encoding
+func fixEncoding(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var b strings.Builder
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && size == 1 {
+			r = decodeWindows1252(s[0])
+		}
+		b.WriteRune(r)
+		s = s[size:]
+	}
+	return b.String()
+}
+
+//ISO-8859-15 differs from Latin-1 in eight code points, most notably the euro sign
+var latin9 = map[byte]rune{0xa4: '€', 0xa6: 'Š', 0xa8: 'š', 0xb4: 'Ž', 0xb8: 'ž', 0xbc: 'Œ', 0xbd: 'œ', 0xbe: 'Ÿ'}
+
+func decodeLatin9(b byte) rune {
+	if r, ok := latin9[b]; ok {
+		return r
+	}
+	return rune(b)
+}
+
+//Reads one character from the source being transcoded
+type runeDecoder func() (rune, error)
+
+//Decodes a single byte character set
+func byteDecoder(r io.ByteReader, decode func(byte) rune) runeDecoder {
+	return func() (rune, error) {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		return decode(b), nil
+	}
+}
+
+//Decodes UTF-16 in either byte order. Unpaired surrogates become U+FFFD.
+func utf16Decoder(r io.ByteReader, bigEndian bool) runeDecoder {
+	unit := func() (rune, error) {
+		first, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		second, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if bigEndian {
+			return rune(first)<<8 | rune(second), nil
+		}
+		return rune(second)<<8 | rune(first), nil
+	}
+	var pending rune = -1 //Unit read while looking for a low surrogate that turned out not to be one
+	return func() (rune, error) {
+		high := pending
+		pending = -1
+		if high < 0 {
+			var err error
+			if high, err = unit(); err != nil {
+				return 0, err
+			}
+		}
+		if !utf16.IsSurrogate(high) {
+			return high, nil
+		}
+		low, err := unit()
+		if err != nil {
+			return utf8.RuneError, nil
+		}
+		if decoded := utf16.DecodeRune(high, low); decoded != utf8.RuneError {
+			return decoded, nil
+		}
+		if !utf16.IsSurrogate(low) {
+			pending = low
+		}
+		return utf8.RuneError, nil
+	}
+}
+
+//Transcodes a stream to UTF-8, one character at a time
+type decodingReader struct {
+	decode  runeDecoder
+	pending []byte
+}
+
+func newDecodingReader(decode runeDecoder) *bufio.Reader {
+	return bufio.NewReaderSize(&decodingReader{decode: decode}, 64*1024)
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(d.pending) > 0 {
+			copied := copy(p[n:], d.pending)
+			d.pending = d.pending[copied:]
+			n += copied
+			continue
+		}
+		r, err := d.decode()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if r < utf8.RuneSelf {
+			p[n] = byte(r)
+			n++
+			continue
+		}
+		var buf [utf8.UTFMax]byte
+		d.pending = append(d.pending[:0], buf[:utf8.EncodeRune(buf[:], r)]...)
+	}
+	return n, nil
+}
@@ -0,0 +1,105 @@
+package easyrss
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//Repairs only the pure-Go backend makes. libxml2 recovers from some of these differently.
+func TestTokenizerTolerance(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		title string
+		items []string
+	}{
+		{"stray ampersand and angle bracket", `<rss><channel><title>a & b < c</title><item><title>1 &lt 2 &unknown; 3</title></item></channel></rss>`,
+			"a & b < c", []string{"1 &lt 2 &unknown; 3"}},
+		{"html entities", `<rss><channel><title>A&nbsp;&mdash;&nbsp;B &eacute;</title><item><title>&hellip;</title></item></channel></rss>`,
+			"A — B é", []string{"…"}},
+		{"unclosed html", `<rss><channel><title>T</title><item><title>One</title><br><img src=x></item><item><title>Two</title></item></channel></rss>`,
+			"T", []string{"One", "Two"}},
+		{"stray end tag", `<rss><channel></item><title>T</title><item><title>One</title></p></item></channel></rss>`,
+			"T", []string{"One"}},
+		{"unquoted attributes", `<rss><channel><title>T</title><item><title>One</title><enclosure url=http://x/1.mp3 length=5></item></channel></rss>`,
+			"T", []string{"One"}},
+		{"invalid utf-8", "<rss><channel><title>caf\xe9 \x93q\x94</title><item><title>ok</title></item></channel></rss>",
+			"café “q”", []string{"ok"}},
+		{"undeclared prefixes", `<rss><channel><title>T</title><itunes:title>I</itunes:title><item><title>One</title><media:title>M</media:title><x:title>X</x:title></item></channel></rss>`,
+			"T", []string{"One"}},
+		{"unknown encoding", `<?xml version="1.0" encoding="windows-1251"?><rss><channel><title>T</title><item><title>x</title></item></channel></rss>`,
+			"T", []string{"x"}},
+		{"utf-16 declared on utf-8", `<?xml version="1.0" encoding="UTF-16"?><rss><channel><title>Grüße</title><item><title>x</title></item></channel></rss>`,
+			"Grüße", []string{"x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeXML(pureBackend{}, []byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := r.Title(); got != tt.title {
+				t.Errorf("title = %q, want %q", got, tt.title)
+			}
+			items, _ := r.Items()
+			if len(items) != len(tt.items) {
+				t.Fatalf("%d items, want %d", len(items), len(tt.items))
+			}
+			for itemID, item := range items {
+				if got, _ := item.Title(); got != tt.items[itemID] {
+					t.Errorf("item %d title = %q, want %q", itemID, got, tt.items[itemID])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizerUnsupportedEncoding(t *testing.T) {
+	docs := map[string]string{
+		"utf-32 big endian":    "\x00\x00\xfe\xff\x00\x00\x00<",
+		"utf-32 little endian": "\xff\xfe\x00\x00<\x00\x00\x00",
+	}
+	for name, doc := range docs {
+		if _, err := decodeXML(pureBackend{}, []byte(doc)); !errors.Is(err, ErrUnsupportedEncoding) {
+			t.Errorf("%s error = %v, want ErrUnsupportedEncoding", name, err)
+		}
+		if _, err := NewDecoder(strings.NewReader(doc)); !errors.Is(err, ErrUnsupportedEncoding) {
+			t.Errorf("%s streamed error = %v, want ErrUnsupportedEncoding", name, err)
+		}
+	}
+}
+
+//Every stray '<' is a text token of its own, which used to make parse time quadratic in the length of the text
+func TestTreeBuilderLongText(t *testing.T) {
+	text := strings.Repeat("a < b ", 200000)
+	r, err := decodeXML(pureBackend{}, []byte(longTextDoc(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := r.Items()
+	if d, _ := items[0].Description(); d != text {
+		t.Fatalf("description has %d bytes, want %d", len(d), len(text))
+	}
+}
+
+//Time per byte should stay flat as the text grows
+func BenchmarkTreeBuilderLongText(b *testing.B) {
+	for _, repeat := range []int{1000, 10000, 100000} {
+		doc := []byte(longTextDoc(strings.Repeat("a < b ", repeat)))
+		b.Run(strconv.Itoa(len(doc)), func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if _, err := decodeXML(pureBackend{}, doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func longTextDoc(text string) string {
+	return "<rss><channel><title>T</title><item><description>" + text + "</description></item></channel></rss>"
+}
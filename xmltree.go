@@ -0,0 +1,285 @@
+package easyrss
+
+import (
+	"io"
+	"strings"
+)
+
+const xmlNS = "http://www.w3.org/XML/1998/namespace"
+
+//Element or text node produced by the pure-Go backend. Children are kept in a linked list so they can be detached cheaply while streaming.
+type xmlNode struct {
+	name      string
+	prefix    string
+	namespace string
+	attrs     []xmlAttr
+	nsDecls   map[string]string //Namespace bindings declared on this element, by prefix
+	text      string
+	isText    bool
+
+	parent      *xmlNode
+	firstChild  *xmlNode
+	lastChild   *xmlNode
+	prevSibling *xmlNode
+	nextSibling *xmlNode
+}
+
+func (n *xmlNode) Name() string {
+	return n.name
+}
+
+func (n *xmlNode) Namespace() string {
+	return n.namespace
+}
+
+func (n *xmlNode) Attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.name == name {
+			return attr.value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) Content() string {
+	if n.isText {
+		return n.text
+	}
+	var b strings.Builder
+	n.writeContent(&b)
+	return b.String()
+}
+
+func (n *xmlNode) writeContent(b *strings.Builder) {
+	for child := n.firstChild; child != nil; child = child.nextSibling {
+		if child.isText {
+			b.WriteString(child.text)
+		} else {
+			child.writeContent(b)
+		}
+	}
+}
+
+func (n *xmlNode) InnerXML() string {
+	var b strings.Builder
+	for child := n.firstChild; child != nil; child = child.nextSibling {
+		child.writeXML(&b)
+	}
+	return b.String()
+}
+
+//Serializes the node and its descendants. Elements outside the XHTML namespace declare their namespace so the markup stays
+//self-contained once taken out of the feed.
+func (n *xmlNode) writeXML(b *strings.Builder) {
+	if n.isText {
+		b.WriteString(xmlEscaper.Replace(n.text))
+		return
+	}
+	b.WriteString("<" + n.name)
+	if n.namespace != xhtmlNS && n.namespace != "" && (n.parent == nil || n.parent.namespace != n.namespace) {
+		b.WriteString(" xmlns=\"" + xmlEscaper.Replace(n.namespace) + "\"")
+	}
+	for _, attr := range n.attrs {
+		name := attr.name
+		if attr.prefix != "" {
+			name = attr.prefix + ":" + name
+		}
+		b.WriteString(" " + name + "=\"" + xmlEscaper.Replace(attr.value) + "\"")
+	}
+	if n.firstChild == nil {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteString(">")
+	for child := n.firstChild; child != nil; child = child.nextSibling {
+		child.writeXML(b)
+	}
+	b.WriteString("</" + n.name + ">")
+}
+
+//Returns the first element child. Text nodes are skipped.
+func (n *xmlNode) FirstChild() node {
+	return elementFrom(n.firstChild)
+}
+
+//Returns the next element sibling. Text nodes are skipped.
+func (n *xmlNode) NextSibling() node {
+	return elementFrom(n.nextSibling)
+}
+
+func elementFrom(n *xmlNode) node {
+	for ; n != nil; n = n.nextSibling {
+		if !n.isText {
+			return n
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) appendChild(child *xmlNode) {
+	child.parent = n
+	child.prevSibling = n.lastChild
+	if n.lastChild != nil {
+		n.lastChild.nextSibling = child
+	} else {
+		n.firstChild = child
+	}
+	n.lastChild = child
+}
+
+//Removes the node from its parent so it can be garbage collected
+func (n *xmlNode) detach() {
+	if n.parent == nil {
+		return
+	}
+	if n.prevSibling != nil {
+		n.prevSibling.nextSibling = n.nextSibling
+	} else {
+		n.parent.firstChild = n.nextSibling
+	}
+	if n.nextSibling != nil {
+		n.nextSibling.prevSibling = n.prevSibling
+	} else {
+		n.parent.lastChild = n.prevSibling
+	}
+	n.parent, n.prevSibling, n.nextSibling = nil, nil, nil
+}
+
+//Resolves a namespace prefix against the declarations in scope. An undeclared prefix falls back to its well-known namespace, or
+//to undeclaredNS.
+func (n *xmlNode) lookupNamespace(prefix string) string {
+	if prefix == "xml" {
+		return xmlNS
+	}
+	for scope := n; scope != nil; scope = scope.parent {
+		if uri, ok := scope.nsDecls[prefix]; ok {
+			return uri
+		}
+	}
+	if prefix == "" {
+		return ""
+	}
+	return undeclaredPrefixNamespace(prefix)
+}
+
+//Builds a tree from tokens one element at a time. Malformed markup is repaired rather than rejected: end tags close the nearest
+//matching open element, stray end tags are dropped and anything left open at EOF is closed.
+type treeBuilder struct {
+	tok    *xmlTokenizer
+	root   *xmlNode
+	open   []*xmlNode      //Currently open elements, innermost last
+	closed []*xmlNode      //Elements closed by the last token, innermost first
+	text   strings.Builder //Character data of the innermost open element since its last child, merged into a single text node
+}
+
+func newTreeBuilder(r io.Reader) *treeBuilder {
+	return &treeBuilder{tok: newXMLTokenizer(r)}
+}
+
+//Returns the depth of the innermost open element, the root being depth 1
+func (b *treeBuilder) depth() int {
+	return len(b.open)
+}
+
+//Consumes tokens until an element is closed and returns it. Returns io.EOF once the document is exhausted.
+func (b *treeBuilder) next() (*xmlNode, error) {
+	for len(b.closed) == 0 {
+		tok, err := b.tok.next()
+		if err == io.EOF {
+			if len(b.open) == 0 {
+				return nil, io.EOF
+			}
+			b.flushText()
+			b.closeTo(0)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == textToken {
+			if len(b.open) > 0 {
+				b.text.WriteString(tok.text)
+			}
+			continue
+		}
+		b.flushText()
+		switch tok.kind {
+		case startToken:
+			b.startElement(tok)
+		case endToken:
+			for i := len(b.open) - 1; i >= 0; i-- {
+				if b.open[i].name == tok.name && b.open[i].prefix == tok.prefix {
+					b.closeTo(i)
+					break
+				}
+			}
+		}
+	}
+	closed := b.closed[0]
+	b.closed = b.closed[1:]
+	return closed, nil
+}
+
+//Appends the pending character data to the innermost open element. The tokenizer splits text at every stray '<', so it is
+//accumulated rather than concatenated token by token.
+func (b *treeBuilder) flushText() {
+	if b.text.Len() == 0 {
+		return
+	}
+	b.open[len(b.open)-1].appendChild(&xmlNode{isText: true, text: b.text.String()})
+	b.text.Reset()
+}
+
+func (b *treeBuilder) startElement(tok xmlToken) {
+	elem := &xmlNode{name: tok.name, prefix: tok.prefix}
+	for _, attr := range tok.attrs {
+		switch {
+		case attr.prefix == "" && attr.name == "xmlns":
+			elem.declareNamespace("", attr.value)
+		case attr.prefix == "xmlns":
+			elem.declareNamespace(attr.name, attr.value)
+		default:
+			elem.attrs = append(elem.attrs, attr)
+		}
+	}
+	if len(b.open) > 0 {
+		b.open[len(b.open)-1].appendChild(elem)
+	} else if b.root == nil {
+		b.root = elem
+	}
+	elem.namespace = elem.lookupNamespace(elem.prefix)
+	if tok.selfClosing {
+		b.closed = append(b.closed, elem)
+		return
+	}
+	b.open = append(b.open, elem)
+}
+
+func (n *xmlNode) declareNamespace(prefix, uri string) {
+	if n.nsDecls == nil {
+		n.nsDecls = make(map[string]string)
+	}
+	n.nsDecls[prefix] = uri
+}
+
+//Closes every open element from index i outwards
+func (b *treeBuilder) closeTo(i int) {
+	for j := len(b.open) - 1; j >= i; j-- {
+		b.closed = append(b.closed, b.open[j])
+	}
+	b.open = b.open[:i]
+}
+
+//Parses a whole document, returning its root element
+func parseTree(r io.Reader) (*xmlNode, error) {
+	b := newTreeBuilder(r)
+	for {
+		if _, err := b.next(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+	return b.root, nil
+}